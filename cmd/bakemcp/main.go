@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"bakemcp/internal/cli"
+	"bakemcp/internal/lint"
 )
 
 // Set via ldflags at build time.
@@ -15,14 +19,44 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint":
+			os.Exit(lintMain(os.Args[2:]))
+		case "inspect":
+			os.Exit(inspectMain(os.Args[2:]))
+		case "diff":
+			os.Exit(diffMain(os.Args[2:]))
+		}
+	}
+
 	var (
-		output     = flag.String("o", "", "output directory (default: current directory)")
-		force      = flag.Bool("f", false, "overwrite non-empty output directory")
+		output      = flag.String("o", "", "output directory (default: current directory)")
+		force       = flag.Bool("f", false, "overwrite non-empty output directory")
+		resources   = flag.Bool("resources", false, "expose read-only GET operations as MCP resources")
+		prompts     = flag.Bool("prompts", false, "generate MCP prompts from tags and response links")
+		projection  = flag.Bool("projection", false, "add a fields argument selecting response fields to tools with a JSON response")
+		confirm     = flag.Bool("confirm", false, "ask the user to confirm each request of a destructive (PUT, PATCH, DELETE) tool")
+		elicit      = flag.Bool("elicit", false, "ask the user for required path and query parameters a tool call leaves out")
+		workflows   = flag.String("workflows", "", "Arazzo document whose workflows become composite tools")
+		overlayPath = flag.String("overlay", "", "OpenAPI Overlay document applied to the input before parsing")
+		templates   = flag.String("templates", "", "directory of templates replacing the built-in ones with the same relative path")
+		name        = flag.String("name", "", "server and npm package name (default: from info.title)")
+		srvVersion  = flag.String("server-version", "", "server and package version (default: from info.version)")
+		instruct    = flag.String("instructions", "", "instructions sent to MCP clients (default: info.description)")
+		report      = flag.Bool("report", false, "write the coverage report to bakemcp-report.json in the output directory")
+		dryRun      = flag.Bool("dry-run", false, "print the files that would be created or changed without writing them")
+		overwrite   = flag.Bool("overwrite-modified", false, "replace generated files even if they were edited since the last run")
+		clean       = flag.Bool("clean", false, "replace or remove every generated file of the previous generation, even edited ones")
+		watch       = flag.Bool("watch", false, "regenerate whenever the inputs or their local $ref files change")
 		showVersion = flag.Bool("version", false, "print version and exit")
 	)
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: bakemcp [options] <openapi-input>\n")
-		fmt.Fprintf(os.Stderr, "  openapi-input  path to OpenAPI 3.x file (JSON or YAML)\n")
+		fmt.Fprintf(os.Stderr, "Usage: bakemcp [options] <openapi-input>...\n")
+		fmt.Fprintf(os.Stderr, "       bakemcp lint [options] <openapi-input>\n")
+		fmt.Fprintf(os.Stderr, "       bakemcp inspect [options] <openapi-input>...\n")
+		fmt.Fprintf(os.Stderr, "       bakemcp diff [options] <old-openapi> <new-openapi>\n")
+		fmt.Fprintf(os.Stderr, "  openapi-input  path to OpenAPI 3.x file (JSON or YAML); use name=path to prefix its tools\n")
 		flag.PrintDefaults()
 	}
 	flag.Parse()
@@ -33,19 +67,150 @@ func main() {
 	}
 
 	args := flag.Args()
-	if len(args) != 1 {
+	if len(args) == 0 {
 		flag.Usage()
 		os.Exit(1)
 	}
+	var inputs []cli.Input
+	for _, arg := range args {
+		inputs = append(inputs, cli.ParseInput(arg))
+	}
 
 	cfg := cli.Config{
-		InputPath: args[0],
-		OutputDir: *output,
-		Force:     *force,
+		Inputs:     inputs,
+		OutputDir:  *output,
+		Force:      *force,
+		Resources:  *resources,
+		Prompts:    *prompts,
+		Projection: *projection,
+		Confirm:    *confirm,
+		Elicit:     *elicit,
+
+		WorkflowsPath: *workflows,
+		OverlayPath:   *overlayPath,
+		TemplatesDir:  *templates,
+		ServerName:    *name,
+		ServerVersion: *srvVersion,
+		Instructions:  *instruct,
+		Report:        *report,
+		DryRun:        *dryRun,
+
+		OverwriteModified: *overwrite,
+		Clean:             *clean,
+	}
+	var code int
+	var err error
+	if *watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		code, err = cli.Watch(ctx, cfg)
+		stop()
+	} else {
+		code, err = cli.Run(cfg)
 	}
-	code, err := cli.Run(cfg)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	}
 	os.Exit(code)
 }
+
+// lintMain runs the lint subcommand and returns its exit code.
+func lintMain(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	var (
+		format    = fs.String("format", "text", "report format: text, json or sarif")
+		failOn    = fs.String("fail-on", "error", "exit non-zero at this severity: error, warning, info or none")
+		maxSchema = fs.Int("max-schema-bytes", lint.DefaultMaxSchemaBytes, "tool input schema size that triggers schema-too-large")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: bakemcp lint [options] <openapi-input>\n")
+		fmt.Fprintf(os.Stderr, "  openapi-input  path to OpenAPI 3.x file (JSON or YAML)\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 1
+	}
+
+	code, err := cli.Lint(cli.LintConfig{
+		InputPath:      fs.Arg(0),
+		Format:         *format,
+		FailOn:         *failOn,
+		MaxSchemaBytes: *maxSchema,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+// inspectMain runs the inspect subcommand and returns its exit code.
+func inspectMain(args []string) int {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	var (
+		format      = fs.String("format", "table", "output format: table or json")
+		overlayPath = fs.String("overlay", "", "OpenAPI Overlay document applied to the input before parsing")
+		resources   = fs.Bool("resources", false, "expose read-only GET operations as MCP resources")
+		projection  = fs.Bool("projection", false, "add a fields argument selecting response fields to tools with a JSON response")
+		confirm     = fs.Bool("confirm", false, "ask the user to confirm each request of a destructive (PUT, PATCH, DELETE) tool")
+		elicit      = fs.Bool("elicit", false, "ask the user for required path and query parameters a tool call leaves out")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: bakemcp inspect [options] <openapi-input>...\n")
+		fmt.Fprintf(os.Stderr, "  openapi-input  path to OpenAPI 3.x file (JSON or YAML); use name=path to prefix its tools\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+	var inputs []cli.Input
+	for _, arg := range fs.Args() {
+		inputs = append(inputs, cli.ParseInput(arg))
+	}
+
+	code, err := cli.Inspect(cli.InspectConfig{
+		Inputs:      inputs,
+		OverlayPath: *overlayPath,
+		Format:      *format,
+		Resources:   *resources,
+		Projection:  *projection,
+		Confirm:     *confirm,
+		Elicit:      *elicit,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+// diffMain runs the diff subcommand and returns its exit code.
+func diffMain(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	var (
+		format         = fs.String("format", "text", "output format: text or json")
+		failOnBreaking = fs.Bool("fail-on-breaking", false, "exit non-zero when a change breaks existing tool callers")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: bakemcp diff [options] <old-openapi> <new-openapi>\n")
+		fmt.Fprintf(os.Stderr, "  old-openapi, new-openapi  paths to OpenAPI 3.x files (JSON or YAML)\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 1
+	}
+
+	code, err := cli.Diff(cli.DiffConfig{
+		OldPath:        fs.Arg(0),
+		NewPath:        fs.Arg(1),
+		Format:         *format,
+		FailOnBreaking: *failOnBreaking,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
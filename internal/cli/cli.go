@@ -4,65 +4,357 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"bakemcp/internal/domain/arazzo"
 	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
 	"bakemcp/internal/domain/openapi"
+	"bakemcp/internal/domain/overlay"
 	"bakemcp/internal/generator/node"
 )
 
+// Input is one OpenAPI document to generate from.
+type Input struct {
+	Path   string
+	Prefix string // Optional tool name prefix
+}
+
+var inputPrefix = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)=(.+)$`)
+
+// ParseInput parses a positional input argument of the form [prefix=]path.
+func ParseInput(arg string) Input {
+	if m := inputPrefix.FindStringSubmatch(arg); m != nil {
+		return Input{Path: m[2], Prefix: m[1]}
+	}
+	return Input{Path: arg}
+}
+
 // Config holds parsed CLI arguments.
 type Config struct {
-	InputPath  string
+	InputPath  string  // Single input; shorthand for a one-element Inputs
+	Inputs     []Input // Several inputs are merged into one server
 	OutputDir  string
 	Force      bool
+	Resources  bool // Expose read-only GET operations as MCP resources instead of tools
+	Prompts    bool // Generate MCP prompts from tags and response links
+	Projection bool // Add the fields argument to every tool with a JSON response
+	Confirm    bool // Ask the user to confirm each request of a destructive tool
+	Elicit     bool // Ask the user for required parameters a call leaves out
+
+	WorkflowsPath string // Arazzo document whose workflows become composite tools
+	OverlayPath   string // OpenAPI Overlay applied to the inputs before parsing
+	TemplatesDir  string // Templates replacing the built-in ones of the same relative path
+
+	ServerName    string // Overrides the server and package name taken from info.title
+	ServerVersion string // Overrides the version taken from info.version
+	Instructions  string // Overrides the instructions taken from info.description
+
+	Report            bool // Also write the coverage report to bakemcp-report.json in OutputDir
+	DryRun            bool // Print the files that would be created or changed instead of writing them
+	OverwriteModified bool // Replace files even when they were modified since the last run
+	Clean             bool // Replace or delete every generated file of the previous generation, even modified ones
+
+	PollInterval time.Duration // How often Watch checks the inputs; defaults to DefaultPollInterval
+	Debounce     time.Duration // How long inputs must stay unchanged before Watch regenerates; defaults to DefaultDebounce
+
+	Log io.Writer // Receives the generation summary; defaults to stderr
+	Out io.Writer // Receives the dry-run file list; defaults to stdout
 }
 
 // Run executes the full flow: read input, parse OpenAPI, check output dir, map operations to tools, generate Node project.
 // Returns exit code (0 = success) and error message for stderr.
 func Run(cfg Config) (exitCode int, err error) {
+	_, code, err := run(cfg)
+	return code, err
+}
+
+// run is Run; it also returns the generated server (nil on failure).
+func run(cfg Config) (*model.MCPServer, int, error) {
 	if cfg.OutputDir == "" {
 		cfg.OutputDir, _ = os.Getwd()
 	}
+	if cfg.Log == nil {
+		cfg.Log = os.Stderr
+	}
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+
+	result, code, err := load(cfg.InputPath, cfg.Inputs, cfg.OverlayPath)
+	if err != nil {
+		return nil, code, err
+	}
 
-	// Read input (file only for now; URL in US2)
-	data, err := os.ReadFile(cfg.InputPath)
+	// Check output dir empty unless --force or generated before (create if missing)
+	if !cfg.DryRun {
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return nil, 1, fmt.Errorf("cannot create output directory: %w", err)
+		}
+	}
+	prev, err := node.LoadManifest(cfg.OutputDir)
+	if err != nil {
+		return nil, 1, err
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) > 0 && prev == nil && !cfg.Force {
+		return nil, 3, fmt.Errorf("output directory is not empty; use --force to overwrite")
+	}
+
+	// Map to MCP resources (optional), tools, prompts (optional) and workflows (optional)
+	opts := mapping.ServerOptions{
+		BuildOptions: mapping.BuildOptions{Projection: cfg.Projection, Confirm: cfg.Confirm, Elicit: cfg.Elicit},
+		Resources:    cfg.Resources,
+		Prompts:      cfg.Prompts,
+	}
+	if cfg.WorkflowsPath != "" {
+		if opts.Workflows, code, err = loadWorkflows(cfg.WorkflowsPath); err != nil {
+			return nil, code, err
+		}
+	}
+	srv, err := mapping.MapServer(result, opts)
+	if err != nil {
+		return nil, 1, err
+	}
+	if err := applyIdentity(srv, cfg); err != nil {
+		return nil, 1, err
+	}
+
+	// Render the Node project (and report) and compare it with the output dir
+	tmpl, err := node.LoadTemplates(cfg.TemplatesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 2, fmt.Errorf("input file not found: %s", cfg.InputPath)
+			return nil, 2, fmt.Errorf("templates directory not found: %s", cfg.TemplatesDir)
+		}
+		return nil, 1, fmt.Errorf("invalid templates: %w", err)
+	}
+	gen, err := node.Prepare(cfg.OutputDir, srv, node.PrepareOptions{
+		Templates: tmpl,
+		Warnings:  result.Warnings,
+		Report:    cfg.Report,
+		Plan:      node.PlanOptions{OverwriteModified: cfg.OverwriteModified, Clean: cfg.Clean, Force: cfg.Force},
+	})
+	if err != nil {
+		return nil, 1, err
+	}
+	plan, report := gen.Plan, gen.Report
+	if cfg.DryRun {
+		printPlan(cfg.Out, cfg.OutputDir, plan)
+		printSummary(cfg.Log, report, "Would generate", cfg.OutputDir)
+		return srv, 0, nil
+	}
+	if conflicts := node.Conflicts(plan); len(conflicts) > 0 {
+		return nil, 3, fmt.Errorf("generated files were modified since the last run or not written by bakemcp: %s; use -overwrite-modified to replace them",
+			strings.Join(conflicts, ", "))
+	}
+	if err := node.Apply(cfg.OutputDir, plan, gen.Manifest, nil); err != nil {
+		return nil, 1, fmt.Errorf("generation failed: %w", err)
+	}
+
+	printSummary(cfg.Log, report, "Generated", cfg.OutputDir)
+	for _, p := range plan {
+		switch {
+		case p.Action == node.ActionKeep:
+			fmt.Fprintf(cfg.Log, "Kept %s: it was modified (use -overwrite-modified to replace it)\n", p.Name)
+		case p.Action == node.ActionDelete:
+			fmt.Fprintf(cfg.Log, "Removed %s: it is no longer generated\n", p.Name)
+		case p.Action == node.ActionDisown:
+			fmt.Fprintf(cfg.Log, "Left %s: it is no longer generated but was modified (use -clean to remove it)\n", p.Name)
+		case p.Name == ReportFile && p.Action != node.ActionRetain:
+			fmt.Fprintf(cfg.Log, "Report written to %s\n", filepath.Join(cfg.OutputDir, p.Name))
+		}
+	}
+
+	return srv, 0, nil
+}
+
+// load parses the inputs (inputPath first, if set) with the optional overlay
+// applied. It fails with exit code 4 when no operations are found.
+func load(inputPath string, inputs []Input, overlayPath string) (*openapi.ParseResult, int, error) {
+	if inputPath != "" {
+		inputs = append([]Input{{Path: inputPath}}, inputs...)
+	}
+	if len(inputs) == 0 {
+		return nil, 2, fmt.Errorf("no input given")
+	}
+	var ov *overlay.Overlay
+	if overlayPath != "" {
+		var code int
+		var err error
+		if ov, code, err = loadOverlay(overlayPath); err != nil {
+			return nil, code, err
+		}
+	}
+	result, code, err := loadInputs(inputs, ov)
+	if err != nil {
+		return nil, code, err
+	}
+	if len(result.Operations) == 0 {
+		return nil, 4, fmt.Errorf("no mappable operations found in OpenAPI spec")
+	}
+	return result, 0, nil
+}
+
+// loadInputs parses every input and merges the results. A single unprefixed
+// input is returned as is; otherwise each input becomes a source named after
+// its prefix (or file name), see openapi.Merge. With several inputs the
+// overlay must name the one it patches in extends.
+func loadInputs(inputs []Input, ov *overlay.Overlay) (*openapi.ParseResult, int, error) {
+	if ov != nil && ov.Extends == "" && len(inputs) > 1 {
+		return nil, 2, fmt.Errorf("the overlay has no extends; with several inputs, set extends to the input it patches")
+	}
+	if ov != nil && ov.Extends != "" {
+		matched := false
+		for _, in := range inputs {
+			matched = matched || overlayApplies(ov, in.Path)
+		}
+		if !matched {
+			return nil, 2, fmt.Errorf("overlay extends %s, which matches no input", ov.Extends)
+		}
+	}
+	if len(inputs) == 1 && inputs[0].Prefix == "" {
+		return loadSpec(inputs[0].Path, ov)
+	}
+	specs := make([]openapi.Named, 0, len(inputs))
+	names := make(map[string]string)
+	for _, in := range inputs {
+		result, code, err := loadSpec(in.Path, ov)
+		if err != nil {
+			return nil, code, err
+		}
+		name := in.Prefix
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(in.Path), filepath.Ext(in.Path))
+		}
+		if other, ok := names[name]; ok {
+			return nil, 2, fmt.Errorf("inputs %s and %s share the name %q; set a prefix with name=path", other, in.Path, name)
+		}
+		names[name] = in.Path
+		specs = append(specs, openapi.Named{Name: name, Prefix: in.Prefix, Result: result})
+	}
+	merged, err := openapi.Merge(specs)
+	if err != nil {
+		return nil, 2, err
+	}
+	return merged, 0, nil
+}
+
+// applyIdentity applies the identity overrides of cfg to srv, which is named
+// after the spec. A merged spec has no info; its server keeps the defaults.
+func applyIdentity(srv *model.MCPServer, cfg Config) error {
+	if cfg.ServerName != "" {
+		srv.Name = mapping.PackageName(cfg.ServerName)
+	}
+	if cfg.ServerVersion != "" {
+		v, ok := mapping.ParseSemVer(cfg.ServerVersion)
+		if !ok {
+			return fmt.Errorf("invalid server version %q; use a semantic version such as 1.2.0", cfg.ServerVersion)
+		}
+		srv.Version = v
+	}
+	if cfg.Instructions != "" {
+		srv.Instructions = cfg.Instructions
+	}
+	return nil
+}
+
+// loadSpec reads and parses one OpenAPI document, applying ov first when it
+// targets this document.
+func loadSpec(path string, ov *overlay.Overlay) (*openapi.ParseResult, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 2, fmt.Errorf("input file not found: %s", path)
+		}
+		return nil, 2, fmt.Errorf("cannot read input: %w", err)
+	}
+	if ov != nil && overlayApplies(ov, path) {
+		if data, err = overlay.Apply(data, ov); err != nil {
+			return nil, 1, fmt.Errorf("cannot apply overlay to %s: %w", path, err)
 		}
-		return 2, fmt.Errorf("cannot read input: %w", err)
 	}
 
 	// Parse OpenAPI 3.x
-	result, err := openapi.Parse(bytes.NewReader(data))
+	result, err := openapi.ParseAt(bytes.NewReader(data), path)
 	if err != nil {
 		if errors.Is(err, openapi.ErrOpenAPI2Unsupported) {
-			return 1, err
+			return nil, 1, err
 		}
-		return 1, fmt.Errorf("invalid OpenAPI: %w", err)
+		return nil, 1, fmt.Errorf("invalid OpenAPI: %w", err)
 	}
+	return result, 0, nil
+}
 
-	if len(result.Operations) == 0 {
-		return 4, fmt.Errorf("no mappable operations found in OpenAPI spec")
+// loadOverlay reads and parses an OpenAPI Overlay document. A local extends is
+// resolved against the directory of the overlay file.
+func loadOverlay(path string) (*overlay.Overlay, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 2, fmt.Errorf("overlay file not found: %s", path)
+		}
+		return nil, 2, fmt.Errorf("cannot read overlay: %w", err)
 	}
+	defer f.Close()
 
-	// Check output dir empty unless --force (create if missing)
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		return 1, fmt.Errorf("cannot create output directory: %w", err)
+	ov, err := overlay.Parse(f)
+	if err != nil {
+		return nil, 1, fmt.Errorf("invalid overlay: %w", err)
 	}
-	entries, _ := os.ReadDir(cfg.OutputDir)
-	if len(entries) > 0 && !cfg.Force {
-		return 3, fmt.Errorf("output directory is not empty; use --force to overwrite")
+	if ov.Extends != "" && !remoteURL(ov.Extends) {
+		target := strings.TrimPrefix(ov.Extends, "file://")
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		if ov.Extends, err = filepath.Abs(target); err != nil {
+			return nil, 2, fmt.Errorf("cannot resolve overlay extends: %w", err)
+		}
+	}
+	return ov, 0, nil
+}
+
+// overlayApplies reports whether ov targets the input at path: overlays
+// without extends apply to every input, local ones to the input at the same
+// absolute path. A remote extends cannot name a local file; it matches the
+// input with the same file name.
+func overlayApplies(ov *overlay.Overlay, path string) bool {
+	switch {
+	case ov.Extends == "":
+		return true
+	case remoteURL(ov.Extends):
+		u, err := url.Parse(ov.Extends)
+		return err == nil && u.Path[strings.LastIndex(u.Path, "/")+1:] == filepath.Base(path)
 	}
+	abs, err := filepath.Abs(path)
+	return err == nil && abs == ov.Extends
+}
 
-	// Map to MCP tools
-	tools := mapping.OperationsToMCPTools(result.Operations, result.BaseURL)
+// remoteURL reports whether s is an http or https URL.
+func remoteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
 
-	// Generate Node project
-	if err := node.Generate(cfg.OutputDir, tools, nil); err != nil {
-		return 1, fmt.Errorf("generation failed: %w", err)
+// loadWorkflows parses the Arazzo document at path, whose workflows become
+// composite tools.
+func loadWorkflows(path string) (*arazzo.Document, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 2, fmt.Errorf("workflows file not found: %s", path)
+		}
+		return nil, 2, fmt.Errorf("cannot read workflows: %w", err)
 	}
+	defer f.Close()
 
-	return 0, nil
+	doc, err := arazzo.Parse(f)
+	if err != nil {
+		return nil, 1, fmt.Errorf("invalid Arazzo workflows: %w", err)
+	}
+	return doc, 0, nil
 }
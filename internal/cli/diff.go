@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"bakemcp/internal/diff"
+	"bakemcp/internal/domain/mapping"
+)
+
+// DiffConfig holds parsed arguments of the diff subcommand.
+type DiffConfig struct {
+	OldPath        string
+	NewPath        string
+	Format         string // text or json
+	FailOnBreaking bool
+	Out            io.Writer
+}
+
+// Diff maps both specs to MCP tools and reports the changes between them. A
+// spec without operations is fine here: every tool counts as added or removed.
+// Returns exit code 1 when FailOnBreaking is set and a change is breaking.
+func Diff(cfg DiffConfig) (exitCode int, err error) {
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+	prev, code, err := loadSpec(cfg.OldPath, nil)
+	if err != nil {
+		return code, err
+	}
+	next, code, err := loadSpec(cfg.NewPath, nil)
+	if err != nil {
+		return code, err
+	}
+
+	prevTools := mapping.Build(prev.Operations, prev.BaseURL, mapping.BuildOptions{})
+	nextTools := mapping.Build(next.Operations, next.BaseURL, mapping.BuildOptions{})
+	changes := diff.Compare(prevTools, nextTools)
+	if err := diff.Write(cfg.Out, cfg.Format, changes); err != nil {
+		return 2, err
+	}
+	if cfg.FailOnBreaking && diff.HasBreaking(changes) {
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"bakemcp/internal/generator/node"
+)
+
+// printPlan lists what regeneration would do with each file.
+func printPlan(w io.Writer, outDir string, plan []node.PlannedFile) {
+	for _, p := range plan {
+		fmt.Fprintf(w, "%-9s %s\n", p.Action, filepath.Join(outDir, p.Name))
+	}
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+// InspectConfig holds parsed arguments of the inspect subcommand.
+type InspectConfig struct {
+	Inputs      []Input
+	OverlayPath string
+	Format      string // table or json
+	Out         io.Writer
+
+	// Mapping options, as for generation
+	Resources  bool // Expose read-only GET operations as MCP resources instead of tools
+	Projection bool // Add the fields argument to every tool with a JSON response
+	Confirm    bool // Ask the user to confirm each request of a destructive tool
+	Elicit     bool // Ask the user for required parameters a call leaves out
+}
+
+// inspectedTool is the JSON form of one tool printed by Inspect.
+type inspectedTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Source      string                 `json:"source,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations map[string]bool        `json:"annotations"`
+}
+
+// Inspect maps the inputs to MCP tools like generation does and prints them,
+// with the arguments the generated tools take, without writing files.
+func Inspect(cfg InspectConfig) (exitCode int, err error) {
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+	if cfg.Format != "" && cfg.Format != "table" && cfg.Format != "json" {
+		return 2, fmt.Errorf("unknown format %q (use table or json)", cfg.Format)
+	}
+	result, code, err := load("", cfg.Inputs, cfg.OverlayPath)
+	if err != nil {
+		return code, err
+	}
+	srv, err := mapping.MapServer(result, mapping.ServerOptions{
+		BuildOptions: mapping.BuildOptions{Projection: cfg.Projection, Confirm: cfg.Confirm, Elicit: cfg.Elicit},
+		Resources:    cfg.Resources,
+	})
+	if err != nil {
+		return 1, err
+	}
+	tools := srv.Tools
+
+	if cfg.Format == "json" {
+		out := make([]inspectedTool, 0, len(tools))
+		for _, t := range tools {
+			out = append(out, inspectedTool{
+				Name:        t.Name,
+				Description: t.Description,
+				Method:      t.Method,
+				Path:        t.Path,
+				Source:      t.Source,
+				InputSchema: toolArguments(t),
+				Annotations: annotationMap(t.Annotations),
+			})
+		}
+		enc := json.NewEncoder(cfg.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]interface{}{"tools": out}); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	tw := tabwriter.NewWriter(cfg.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tMETHOD\tPATH\tARGUMENTS\tHINTS")
+	for _, t := range tools {
+		path := t.Path
+		if t.Source != "" {
+			path = t.Source + ":" + path
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.Method, path, argumentSummary(toolArguments(t)), hintSummary(t.Annotations))
+	}
+	if err := tw.Flush(); err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// toolArguments returns the JSON Schema of the arguments the generated tool
+// takes. Like the generator, it puts the request body's properties next to
+// the parameters instead of nesting them under body; the arguments the server
+// handles itself (fields, confirm, fetchAll, maxPages) come from InputSchema.
+func toolArguments(t *model.MCPTool) map[string]interface{} {
+	if t.Body == nil {
+		return t.InputSchema
+	}
+	props := make(map[string]interface{})
+	required := make(map[string]bool)
+	for _, p := range t.Params {
+		props[p.Name] = p.Schema
+	}
+	for _, name := range requiredNames(t.InputSchema) {
+		required[name] = name != "body"
+	}
+	bodyProps, _ := t.Body.Schema["properties"].(map[string]interface{})
+	for name, prop := range bodyProps {
+		props[name] = prop
+	}
+	for _, name := range requiredNames(t.Body.Schema) {
+		required[name] = true
+	}
+	inputProps, _ := t.InputSchema["properties"].(map[string]interface{})
+	for name, prop := range inputProps {
+		if _, ok := props[name]; !ok && name != "body" {
+			props[name] = prop
+		}
+	}
+	var names []string
+	for name := range required {
+		if required[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(names) > 0 {
+		schema["required"] = names
+	}
+	return schema
+}
+
+// requiredNames returns the required list of schema.
+func requiredNames(schema map[string]interface{}) []string {
+	switch req := schema["required"].(type) {
+	case []string:
+		return req
+	case []interface{}:
+		var names []string
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// argumentSummary renders schema properties as "name*:type" (* = required).
+func argumentSummary(schema map[string]interface{}) string {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return "-"
+	}
+	required := make(map[string]bool)
+	for _, r := range requiredNames(schema) {
+		required[r] = true
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		typ := "any"
+		if prop, ok := props[name].(map[string]interface{}); ok {
+			if t, ok := prop["type"].(string); ok {
+				typ = t
+			}
+		}
+		if required[name] {
+			name += "*"
+		}
+		parts = append(parts, name+":"+typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hintSummary lists the hints set on a, e.g. "read-only, idempotent".
+func hintSummary(a *model.MCPToolAnnotations) string {
+	if a == nil {
+		return "-"
+	}
+	var hints []string
+	if a.ReadOnlyHint {
+		hints = append(hints, "read-only")
+	}
+	if a.DestructiveHint {
+		hints = append(hints, "destructive")
+	}
+	if a.IdempotentHint {
+		hints = append(hints, "idempotent")
+	}
+	if len(hints) == 0 {
+		return "-"
+	}
+	return strings.Join(hints, ", ")
+}
+
+func annotationMap(a *model.MCPToolAnnotations) map[string]bool {
+	if a == nil {
+		return nil
+	}
+	return map[string]bool{
+		"readOnlyHint":    a.ReadOnlyHint,
+		"destructiveHint": a.DestructiveHint,
+		"idempotentHint":  a.IdempotentHint,
+		"openWorldHint":   a.OpenWorldHint,
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"bakemcp/internal/lint"
+)
+
+// LintConfig holds parsed arguments of the lint subcommand.
+type LintConfig struct {
+	InputPath      string
+	Format         string // text, json or sarif
+	FailOn         string // error, warning, info or none
+	MaxSchemaBytes int    // schema-too-large threshold; 0 uses the default
+	Out            io.Writer
+}
+
+// Lint validates the OpenAPI spec and reports problems that would produce a
+// broken or degraded MCP server. Returns exit code 1 when a finding reaches
+// the FailOn severity.
+func Lint(cfg LintConfig) (exitCode int, err error) {
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+	failOn := lint.Error
+	if cfg.FailOn != "" && cfg.FailOn != "none" {
+		if failOn, err = lint.ParseSeverity(cfg.FailOn); err != nil {
+			return 2, err
+		}
+	}
+
+	data, err := os.ReadFile(cfg.InputPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 2, fmt.Errorf("input file not found: %s", cfg.InputPath)
+		}
+		return 2, fmt.Errorf("cannot read input: %w", err)
+	}
+
+	findings := lint.Lint(data, lint.Options{MaxSchemaBytes: cfg.MaxSchemaBytes, Path: cfg.InputPath})
+	if err := lint.Write(cfg.Out, cfg.Format, findings, cfg.InputPath); err != nil {
+		return 2, err
+	}
+	if cfg.FailOn != "none" && lint.Failed(findings, failOn) {
+		return 1, nil
+	}
+	return 0, nil
+}
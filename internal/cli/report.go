@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/generator/node"
+)
+
+// ReportFile is the name of the coverage report written with Config.Report.
+const ReportFile = node.ReportFile
+
+// printSummary writes a human-readable summary of r to w; verb describes
+// the run (e.g. Generated).
+func printSummary(w io.Writer, r *model.Report, verb, outDir string) {
+	fmt.Fprintf(w, "%s %d tools, %d resources, %d prompts and %d workflows in %s\n",
+		verb, r.Tools, r.Resources, r.Prompts, r.Workflows, outDir)
+	if len(r.Warnings) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d warnings (the server cannot fully support these):\n", len(r.Warnings))
+	for _, wn := range r.Warnings {
+		op := wn.Operation
+		if wn.Source != "" {
+			op = wn.Source + ": " + op
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s\n    at #%s\n", wn.Stage, op, wn.Message, wn.Pointer)
+	}
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"bakemcp/internal/diff"
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/domain/openapi"
+)
+
+// Watch timing defaults (see Config.PollInterval and Config.Debounce).
+const (
+	DefaultPollInterval = 500 * time.Millisecond
+	DefaultDebounce     = 300 * time.Millisecond
+)
+
+// Watch generates once and then regenerates whenever an input, the overlay,
+// the workflows document or a local file referenced through $ref changes,
+// printing which tools changed. Failed runs are reported to cfg.Log and
+// watching continues. It returns when ctx is done.
+func Watch(ctx context.Context, cfg Config) (exitCode int, err error) {
+	if cfg.Log == nil {
+		cfg.Log = os.Stderr
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = DefaultDebounce
+	}
+
+	files := watchedFiles(cfg)
+	fmt.Fprintf(cfg.Log, "Watching %d files (Ctrl-C to stop)\n", len(files))
+	var tools []*model.MCPTool
+	generated := false
+	regenerate := func() {
+		srv, _, err := run(cfg)
+		if err != nil {
+			fmt.Fprintf(cfg.Log, "Error: %v\nWaiting for changes...\n", err)
+			return
+		}
+		if generated {
+			fmt.Fprintln(cfg.Log, "Tool changes:")
+			_ = diff.Write(cfg.Log, "text", diff.Compare(tools, srv.Tools))
+		}
+		tools, generated = srv.Tools, true
+	}
+
+	regenerate()
+	last := snapshot(files)
+	var changedAt time.Time
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, nil
+		case <-ticker.C:
+		}
+		if cur := snapshot(files); !maps.Equal(cur, last) {
+			last, changedAt = cur, time.Now()
+			continue
+		}
+		if changedAt.IsZero() || time.Since(changedAt) < cfg.Debounce {
+			continue
+		}
+		changedAt = time.Time{}
+		fmt.Fprintf(cfg.Log, "\n[%s] Change detected, regenerating\n", time.Now().Format("15:04:05"))
+		regenerate()
+		// The $refs may have changed; start watching new files from here.
+		if next := watchedFiles(cfg); !slices.Equal(next, files) {
+			files, last = next, snapshot(next)
+		}
+	}
+}
+
+// watchedFiles lists the files a run reads: the inputs and the local files
+// they reference, the overlay, the workflows document and the templates.
+func watchedFiles(cfg Config) []string {
+	var files []string
+	inputs := cfg.Inputs
+	if cfg.InputPath != "" {
+		inputs = append([]Input{{Path: cfg.InputPath}}, inputs...)
+	}
+	for _, in := range inputs {
+		files = append(files, in.Path)
+		files = append(files, openapi.LocalRefs(in.Path)...)
+	}
+	for _, path := range []string{cfg.OverlayPath, cfg.WorkflowsPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	if cfg.TemplatesDir != "" {
+		_ = filepath.WalkDir(cfg.TemplatesDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(path) == ".tmpl" {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// snapshot maps each file to the hash of its content ("" when unreadable).
+func snapshot(files []string) map[string]string {
+	out := make(map[string]string, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			out[f] = ""
+			continue
+		}
+		out[f] = fmt.Sprintf("%x", sha256.Sum256(data))
+	}
+	return out
+}
@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Change kinds reported by Compare.
+const (
+	ToolAdded          = "tool-added"
+	ToolRemoved        = "tool-removed"
+	ToolRenamed        = "tool-renamed"
+	EndpointChanged    = "endpoint-changed"
+	DescriptionChanged = "description-changed"
+	ArgumentAdded      = "argument-added"
+	ArgumentRemoved    = "argument-removed"
+	ArgumentRequired   = "argument-required"
+	ArgumentOptional   = "argument-optional"
+	TypeChanged        = "type-changed"
+	EnumNarrowed       = "enum-narrowed"
+	EnumWidened        = "enum-widened"
+)
+
+// Change is one difference between two versions of a tool set, as seen by agents.
+type Change struct {
+	Kind     string `json:"kind"`
+	Tool     string `json:"tool"`               // Tool name in the new spec (old name for removed tools)
+	Argument string `json:"argument,omitempty"` // Argument path, e.g. body.items[].sku
+	Breaking bool   `json:"breaking"`           // Agents written against the old tools may fail
+	Message  string `json:"message"`
+}
+
+// Compare reports how the tools in next differ from the tools in prev. Tools
+// are matched by name; a removed and an added tool calling the same endpoint
+// count as a rename. Argument schemas are compared recursively.
+func Compare(prev, next []*model.MCPTool) []Change {
+	var out []Change
+	add := func(c Change) { out = append(out, c) }
+
+	oldByName := make(map[string]*model.MCPTool, len(prev))
+	for _, t := range prev {
+		oldByName[t.Name] = t
+	}
+	newByName := make(map[string]*model.MCPTool, len(next))
+	for _, t := range next {
+		newByName[t.Name] = t
+	}
+
+	// Unmatched tools: pair removed and added tools on the same endpoint as renames.
+	var added []*model.MCPTool
+	for _, t := range next {
+		if oldByName[t.Name] == nil {
+			added = append(added, t)
+		}
+	}
+	removedByEndpoint := make(map[string]*model.MCPTool)
+	var removed []*model.MCPTool
+	for _, t := range prev {
+		if newByName[t.Name] == nil {
+			removed = append(removed, t)
+			removedByEndpoint[endpoint(t)] = t
+		}
+	}
+	renamedFrom := make(map[string]bool)
+	for _, t := range added {
+		if old := removedByEndpoint[endpoint(t)]; old != nil && !renamedFrom[old.Name] {
+			renamedFrom[old.Name] = true
+			add(Change{Kind: ToolRenamed, Tool: t.Name, Breaking: true,
+				Message: fmt.Sprintf("tool %s was renamed to %s", old.Name, t.Name)})
+			compareTool(add, old, t)
+			continue
+		}
+		add(Change{Kind: ToolAdded, Tool: t.Name, Message: fmt.Sprintf("tool %s was added", t.Name)})
+	}
+	for _, t := range removed {
+		if !renamedFrom[t.Name] {
+			add(Change{Kind: ToolRemoved, Tool: t.Name, Breaking: true, Message: fmt.Sprintf("tool %s was removed", t.Name)})
+		}
+	}
+	for _, t := range next {
+		if old := oldByName[t.Name]; old != nil {
+			compareTool(add, old, t)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Tool != out[j].Tool {
+			return out[i].Tool < out[j].Tool
+		}
+		return out[i].Argument < out[j].Argument
+	})
+	return out
+}
+
+// HasBreaking reports whether any change is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func endpoint(t *model.MCPTool) string {
+	return t.Source + " " + t.Method + " " + t.Path
+}
+
+func compareTool(add func(Change), old, t *model.MCPTool) {
+	if endpoint(old) != endpoint(t) {
+		add(Change{Kind: EndpointChanged, Tool: t.Name,
+			Message: fmt.Sprintf("now calls %s %s instead of %s %s", t.Method, t.Path, old.Method, old.Path)})
+	}
+	if old.Description != t.Description {
+		add(Change{Kind: DescriptionChanged, Tool: t.Name, Message: "description changed"})
+	}
+	compareProperties(add, t.Name, "", old.InputSchema, t.InputSchema)
+}
+
+// compareProperties compares the properties of two object schemas; prefix is
+// the argument path of the object ("" for the tool arguments).
+func compareProperties(add func(Change), tool, prefix string, old, next map[string]interface{}) {
+	oldProps, _ := old["properties"].(map[string]interface{})
+	newProps, _ := next["properties"].(map[string]interface{})
+	oldReq, newReq := required(old), required(next)
+
+	for _, name := range sortedKeys(newProps) {
+		arg := prefix + name
+		newSchema, _ := newProps[name].(map[string]interface{})
+		oldRaw, existed := oldProps[name]
+		if !existed {
+			if newReq[name] {
+				add(Change{Kind: ArgumentAdded, Tool: tool, Argument: arg, Breaking: true,
+					Message: fmt.Sprintf("new required argument %s", arg)})
+			} else {
+				add(Change{Kind: ArgumentAdded, Tool: tool, Argument: arg,
+					Message: fmt.Sprintf("new optional argument %s", arg)})
+			}
+			continue
+		}
+		switch {
+		case newReq[name] && !oldReq[name]:
+			add(Change{Kind: ArgumentRequired, Tool: tool, Argument: arg, Breaking: true,
+				Message: fmt.Sprintf("argument %s is now required", arg)})
+		case !newReq[name] && oldReq[name]:
+			add(Change{Kind: ArgumentOptional, Tool: tool, Argument: arg,
+				Message: fmt.Sprintf("argument %s is now optional", arg)})
+		}
+		oldSchema, _ := oldRaw.(map[string]interface{})
+		compareSchema(add, tool, arg, oldSchema, newSchema)
+	}
+	for _, name := range sortedKeys(oldProps) {
+		if _, ok := newProps[name]; !ok {
+			arg := prefix + name
+			add(Change{Kind: ArgumentRemoved, Tool: tool, Argument: arg, Breaking: true,
+				Message: fmt.Sprintf("argument %s was removed", arg)})
+		}
+	}
+}
+
+// compareSchema compares the type and enum of one argument, then recurses
+// into object properties and array items.
+func compareSchema(add func(Change), tool, arg string, old, next map[string]interface{}) {
+	oldType, newType := schemaType(old), schemaType(next)
+	if oldType != newType {
+		// Widening to any value, or from integer to number, accepts every old value.
+		breaking := newType != "any" && !(oldType == "integer" && newType == "number")
+		add(Change{Kind: TypeChanged, Tool: tool, Argument: arg, Breaking: breaking,
+			Message: fmt.Sprintf("argument %s changed type from %s to %s", arg, oldType, newType)})
+		return
+	}
+
+	oldEnum, hadEnum := enumValues(old)
+	newEnum, hasEnum := enumValues(next)
+	switch {
+	case hasEnum && !hadEnum:
+		add(Change{Kind: EnumNarrowed, Tool: tool, Argument: arg, Breaking: true,
+			Message: fmt.Sprintf("argument %s is now limited to %s", arg, strings.Join(newEnum, ", "))})
+	case hadEnum && !hasEnum:
+		add(Change{Kind: EnumWidened, Tool: tool, Argument: arg,
+			Message: fmt.Sprintf("argument %s is no longer limited to fixed values", arg)})
+	case hadEnum && hasEnum:
+		if gone := missing(oldEnum, newEnum); len(gone) > 0 {
+			add(Change{Kind: EnumNarrowed, Tool: tool, Argument: arg, Breaking: true,
+				Message: fmt.Sprintf("argument %s no longer accepts %s", arg, strings.Join(gone, ", "))})
+		}
+		if extra := missing(newEnum, oldEnum); len(extra) > 0 {
+			add(Change{Kind: EnumWidened, Tool: tool, Argument: arg,
+				Message: fmt.Sprintf("argument %s now also accepts %s", arg, strings.Join(extra, ", "))})
+		}
+	}
+
+	switch newType {
+	case "object":
+		compareProperties(add, tool, arg+".", old, next)
+	case "array":
+		oldItems, _ := old["items"].(map[string]interface{})
+		newItems, _ := next["items"].(map[string]interface{})
+		compareSchema(add, tool, arg+"[]", oldItems, newItems)
+	}
+}
+
+func schemaType(s map[string]interface{}) string {
+	if t, ok := s["type"].(string); ok && t != "" {
+		return t
+	}
+	return "any"
+}
+
+func enumValues(s map[string]interface{}) ([]string, bool) {
+	arr, ok := s["enum"].([]interface{})
+	if !ok || len(arr) == 0 {
+		return nil, false
+	}
+	out := make([]string, 0, len(arr))
+	for _, v := range arr {
+		out = append(out, fmt.Sprint(v))
+	}
+	return out, true
+}
+
+// missing returns the values of a that are not in b.
+func missing(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, v := range b {
+		in[v] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !in[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func required(s map[string]interface{}) map[string]bool {
+	out := make(map[string]bool)
+	switch req := s["required"].(type) {
+	case []string:
+		for _, r := range req {
+			out[r] = true
+		}
+	case []interface{}:
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				out[name] = true
+			}
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Write renders changes in the given format (text or json).
+func Write(w io.Writer, format string, changes []Change) error {
+	switch format {
+	case "text", "":
+		return writeText(w, changes)
+	case "json":
+		return writeJSON(w, changes)
+	}
+	return fmt.Errorf("unknown format %q (use text or json)", format)
+}
+
+func count(changes []Change) (breaking, nonBreaking int) {
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		} else {
+			nonBreaking++
+		}
+	}
+	return breaking, nonBreaking
+}
+
+func writeText(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		label := "ok"
+		if c.Breaking {
+			label = "BREAKING"
+		}
+		fmt.Fprintf(w, "%-8s %s: %s\n", label, c.Tool, c.Message)
+	}
+	breaking, nonBreaking := count(changes)
+	_, err := fmt.Fprintf(w, "%d breaking, %d non-breaking changes\n", breaking, nonBreaking)
+	return err
+}
+
+func writeJSON(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+	breaking, nonBreaking := count(changes)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Breaking    int      `json:"breaking"`
+		NonBreaking int      `json:"nonBreaking"`
+		Changes     []Change `json:"changes"`
+	}{breaking, nonBreaking, changes})
+}
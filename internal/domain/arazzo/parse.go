@@ -0,0 +1,99 @@
+package arazzo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the subset of an Arazzo 1.x document used to build composite tools.
+type Document struct {
+	Arazzo    string     `yaml:"arazzo"`
+	Workflows []Workflow `yaml:"workflows"`
+}
+
+// Workflow is a named sequence of API calls.
+type Workflow struct {
+	WorkflowID  string                 `yaml:"workflowId"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Inputs      map[string]interface{} `yaml:"inputs"` // JSON Schema for workflow inputs
+	Steps       []Step                 `yaml:"steps"`
+	Outputs     map[string]string      `yaml:"outputs"` // Output name -> runtime expression
+}
+
+// Step is a single API call within a workflow.
+type Step struct {
+	StepID          string            `yaml:"stepId"`
+	Description     string            `yaml:"description"`
+	OperationID     string            `yaml:"operationId"`
+	Parameters      []Parameter       `yaml:"parameters"`
+	RequestBody     *RequestBody      `yaml:"requestBody"`
+	SuccessCriteria []Criterion       `yaml:"successCriteria"`
+	Outputs         map[string]string `yaml:"outputs"` // Output name -> runtime expression
+}
+
+// Parameter is a value passed to a step's operation. Value is either a literal
+// or a runtime expression (e.g. $inputs.petId, $steps.login.outputs.token).
+type Parameter struct {
+	Name  string      `yaml:"name"`
+	In    string      `yaml:"in"`
+	Value interface{} `yaml:"value"`
+}
+
+// RequestBody is the payload sent by a step; string leaves may be runtime
+// expressions or embed them as {$expression}.
+type RequestBody struct {
+	ContentType string      `yaml:"contentType"`
+	Payload     interface{} `yaml:"payload"`
+}
+
+// Criterion is a step success condition (e.g. $statusCode == 200).
+type Criterion struct {
+	Condition string `yaml:"condition"`
+	Type      string `yaml:"type"`
+}
+
+// Parse reads an Arazzo 1.x document from r (YAML or JSON). Step operationIds
+// qualified with a source description ($sourceDescriptions.api.getPet) are
+// reduced to the bare operationId.
+func Parse(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.Arazzo, "1.") {
+		return nil, fmt.Errorf("unsupported Arazzo version %q; use Arazzo 1.x", doc.Arazzo)
+	}
+	if len(doc.Workflows) == 0 {
+		return nil, fmt.Errorf("no workflows found in Arazzo document")
+	}
+	for i := range doc.Workflows {
+		wf := &doc.Workflows[i]
+		if wf.WorkflowID == "" {
+			return nil, fmt.Errorf("workflows[%d]: missing workflowId", i)
+		}
+		if len(wf.Steps) == 0 {
+			return nil, fmt.Errorf("workflow %s: no steps", wf.WorkflowID)
+		}
+		for j := range wf.Steps {
+			st := &wf.Steps[j]
+			if st.StepID == "" {
+				return nil, fmt.Errorf("workflow %s: steps[%d]: missing stepId", wf.WorkflowID, j)
+			}
+			if st.OperationID == "" {
+				return nil, fmt.Errorf("workflow %s: step %s: only operationId steps are supported", wf.WorkflowID, st.StepID)
+			}
+			if k := strings.LastIndex(st.OperationID, "."); strings.HasPrefix(st.OperationID, "$sourceDescriptions.") && k >= 0 {
+				st.OperationID = st.OperationID[k+1:]
+			}
+		}
+	}
+	return &doc, nil
+}
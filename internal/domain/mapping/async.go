@@ -0,0 +1,59 @@
+package mapping
+
+import "bakemcp/internal/domain/model"
+
+// extAsync marks an operation as asynchronous (true), or not (false, for an
+// operation documenting 202 that completes synchronously), or configures the
+// polling with an object of statusUrl, interval and timeout.
+const extAsync = "x-mcp-async"
+
+// asyncOptions returns how the tool of op waits for the operation to finish:
+// operations documenting a 202 Accepted response, or with x-mcp-async, poll
+// their status URL. Invalid values are ignored with a warning.
+func asyncOptions(op *model.Operation) (*model.MCPAsync, []model.Warning) {
+	v, ok := op.Extensions[extAsync]
+	if !ok {
+		if op.Accepted {
+			return &model.MCPAsync{}, nil
+		}
+		return nil, nil
+	}
+	var warnings []model.Warning
+	invalid := func(format string, args ...interface{}) {
+		warnings = append(warnings, warning(op, "/"+extAsync, "%s "+format, append([]interface{}{extAsync}, args...)...))
+	}
+	switch v := v.(type) {
+	case bool:
+		if !v {
+			return nil, nil
+		}
+		return &model.MCPAsync{}, nil
+	case map[string]interface{}:
+		a := &model.MCPAsync{}
+		if raw, ok := v["statusUrl"]; ok {
+			if s, ok := raw.(string); ok && s != "" {
+				a.StatusURL = s
+			} else {
+				invalid("statusUrl must be a dotted path in the response; it is ignored")
+			}
+		}
+		for _, f := range []struct {
+			key string
+			dst *int
+		}{{"interval", &a.IntervalMs}, {"timeout", &a.TimeoutMs}} {
+			if raw, ok := v[f.key]; ok {
+				if ms, ok := durationMs(raw); ok && ms > 0 {
+					*f.dst = ms
+				} else {
+					invalid(`%s must be a positive number of milliseconds or a duration such as "5s"; the default applies`, f.key)
+				}
+			}
+		}
+		return a, warnings
+	}
+	invalid("must be a boolean or an object with statusUrl, interval and timeout; it is ignored")
+	if op.Accepted {
+		return &model.MCPAsync{}, warnings
+	}
+	return nil, warnings
+}
@@ -0,0 +1,67 @@
+package mapping
+
+import (
+	"fmt"
+
+	"bakemcp/internal/domain/arazzo"
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/domain/openapi"
+)
+
+// BuildOptions turn on the opt-in passes of Build for every operation; the
+// x-mcp-projection, x-mcp-confirm and x-mcp-elicit extensions still decide per
+// operation. The zero value applies the extensions only.
+type BuildOptions struct {
+	Projection bool // Add the fields argument to every tool with a JSON response
+	Confirm    bool // Ask the user to confirm each request of a destructive tool
+	Elicit     bool // Ask the user for required parameters a call leaves out
+}
+
+// Build maps ops to MCP tools with OperationsToMCPTools and applies the
+// projection, confirmation and elicitation passes. Every command that maps
+// tools goes through it, so they all see the same tools and warnings.
+func Build(ops []*model.Operation, baseURL string, opts BuildOptions) []*model.MCPTool {
+	tools := OperationsToMCPTools(ops, baseURL)
+	ApplyProjection(tools, ops, opts.Projection)
+	ApplyConfirmation(tools, ops, opts.Confirm)
+	ApplyElicitation(tools, ops, opts.Elicit)
+	return tools
+}
+
+// ServerOptions control MapServer.
+type ServerOptions struct {
+	BuildOptions
+	Resources bool             // Expose read-only GET operations as MCP resources instead of tools
+	Prompts   bool             // Generate MCP prompts from tags and response links
+	Workflows *arazzo.Document // Workflows that become composite tools (optional)
+}
+
+// MapServer maps a parsed spec (one document, or several merged) to an MCP
+// server named after spec.Info: its rate limit, resources, tools, prompts and
+// workflows. Problems with the document's x-ratelimit extension are added to
+// spec.Warnings. It fails when a workflow cannot be resolved.
+func MapServer(spec *openapi.ParseResult, opts ServerOptions) (*model.MCPServer, error) {
+	ops := spec.Operations
+	srv := &model.MCPServer{}
+	ApplyInfo(srv, spec.Info)
+	var limitWarnings []model.Warning
+	srv.RateLimit, limitWarnings = DocumentRateLimit(spec.Extensions)
+	spec.Warnings = append(spec.Warnings, limitWarnings...)
+	if opts.Resources {
+		srv.Resources, ops = OperationsToMCPResources(ops, spec.BaseURL)
+	}
+	srv.Tools = Build(ops, spec.BaseURL, opts.BuildOptions)
+	if opts.Prompts {
+		// Prompts cover every operation; links to resources name their URI.
+		srv.Prompts = OperationsToMCPPrompts(spec.Operations, srv.Tools, srv.Resources, spec.Tags)
+	}
+	if opts.Workflows != nil {
+		// Steps resolve against all operations, including those exposed as resources.
+		var err error
+		srv.Workflows, err = WorkflowsToMCPWorkflows(opts.Workflows.Workflows, spec.Operations, srv.Tools, spec.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Arazzo workflows: %w", err)
+		}
+	}
+	return srv, nil
+}
@@ -0,0 +1,80 @@
+package mapping
+
+import (
+	"fmt"
+
+	"bakemcp/internal/domain/model"
+)
+
+// ConfirmArgument is the tool argument confirming a request when the client
+// cannot ask the user itself.
+const ConfirmArgument = "confirm"
+
+// extConfirm turns confirmation on (true) or off (false) for one operation,
+// whatever its method.
+const extConfirm = "x-mcp-confirm"
+
+// ApplyConfirmation makes the tools ask the user before each request: the
+// destructive ones (PUT, PATCH and DELETE) when destructive is set, and those
+// whose operation sets x-mcp-confirm: true; x-mcp-confirm: false opts an
+// operation out. tools and ops are parallel, as passed to and returned by
+// OperationsToMCPTools. Problems are added to the warnings of the tool.
+func ApplyConfirmation(tools []*model.MCPTool, ops []*model.Operation, destructive bool) {
+	for i, t := range tools {
+		op := ops[i]
+		enable := destructive && t.Annotations != nil && t.Annotations.DestructiveHint
+		pointer := ""
+		if v, ok := op.Extensions[extConfirm]; ok {
+			b, isBool := v.(bool)
+			if !isBool {
+				t.Warnings = append(t.Warnings, warning(op, "/"+extConfirm, "%s must be a boolean; the default applies", extConfirm))
+			} else {
+				enable, pointer = b, "/"+extConfirm
+			}
+		}
+		if !enable {
+			continue
+		}
+		if problem := confirmProblem(t); problem != "" {
+			t.Warnings = append(t.Warnings, warning(op, pointer,
+				"%s; the tool cannot ask for confirmation and runs requests unconfirmed", problem))
+			continue
+		}
+		enableConfirmation(t)
+	}
+}
+
+// confirmProblem explains why t cannot get a confirm argument, or returns "".
+func confirmProblem(t *model.MCPTool) string {
+	for _, p := range t.Params {
+		if p.Name == ConfirmArgument {
+			return fmt.Sprintf("the operation already has a %s parameter named %q", p.In, ConfirmArgument)
+		}
+	}
+	if t.Body != nil {
+		if props, _ := t.Body.Schema["properties"].(map[string]interface{}); props[ConfirmArgument] != nil {
+			return fmt.Sprintf("the request body already has a property named %q", ConfirmArgument)
+		}
+	}
+	return ""
+}
+
+// enableConfirmation adds the optional confirm argument to t and says in its
+// description that requests are confirmed first.
+func enableConfirmation(t *model.MCPTool) {
+	t.Confirm = true
+	t.Description += "\n\nThe user is asked to confirm each request before it is sent. If the client cannot ask, the tool returns the request instead: show it to the user and call again with " +
+		ConfirmArgument + " set to true once they agree."
+	props, _ := t.InputSchema["properties"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+		if t.InputSchema == nil {
+			t.InputSchema = map[string]interface{}{"type": "object"}
+		}
+		t.InputSchema["properties"] = props
+	}
+	props[ConfirmArgument] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Set to true once the user has confirmed the request; only needed when the client cannot ask the user",
+	}
+}
@@ -0,0 +1,107 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// extElicit turns asking the user for missing required arguments on (true)
+// or off (false) for one operation.
+const extElicit = "x-mcp-elicit"
+
+// ApplyElicitation makes the tools ask the user, through MCP elicitation, for
+// required path and query parameters a call leaves out: every tool when all is
+// set, and those whose operation sets x-mcp-elicit: true; x-mcp-elicit: false
+// opts an operation out. Only parameters of a simple type (string, number,
+// integer, boolean or an enum) can be asked for; they become optional
+// arguments. tools and ops are parallel, as passed to and returned by
+// OperationsToMCPTools. Problems are added to the warnings of the tool.
+func ApplyElicitation(tools []*model.MCPTool, ops []*model.Operation, all bool) {
+	for i, t := range tools {
+		op := ops[i]
+		enable, explicit := all, false
+		if v, ok := op.Extensions[extElicit]; ok {
+			b, isBool := v.(bool)
+			if !isBool {
+				t.Warnings = append(t.Warnings, warning(op, "/"+extElicit, "%s must be a boolean; the default applies", extElicit))
+			} else {
+				enable, explicit = b, true
+			}
+		}
+		if !enable {
+			continue
+		}
+		var names []string
+		for _, p := range t.Params {
+			if p.Required && (p.In == "path" || p.In == "query") && elicitable(p.Schema) {
+				names = append(names, p.Name)
+			}
+		}
+		if len(names) == 0 {
+			if explicit {
+				t.Warnings = append(t.Warnings, warning(op, "/"+extElicit,
+					"%s: the operation has no required path or query parameter of a simple type to ask for", extElicit))
+			}
+			continue
+		}
+		enableElicitation(t, names)
+	}
+}
+
+// elicitable reports whether a parameter of schema can be asked for: MCP
+// elicitation only takes strings, numbers, booleans and enums.
+func elicitable(schema map[string]interface{}) bool {
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, v := range enum {
+			switch v.(type) {
+			case string, float64, int, bool:
+			default:
+				return false
+			}
+		}
+		return len(enum) > 0
+	}
+	switch schema["type"] {
+	case "string", "number", "integer", "boolean":
+		return true
+	}
+	return false
+}
+
+// enableElicitation makes the names arguments of t optional and says in its
+// description that the user is asked for them.
+func enableElicitation(t *model.MCPTool, names []string) {
+	t.Elicit = names
+	elicited := make(map[string]bool, len(names))
+	for _, name := range names {
+		elicited[name] = true
+	}
+	if required, ok := t.InputSchema["required"].([]string); ok {
+		var kept []string
+		for _, name := range required {
+			if !elicited[name] {
+				kept = append(kept, name)
+			}
+		}
+		if kept == nil {
+			delete(t.InputSchema, "required")
+		} else {
+			t.InputSchema["required"] = kept
+		}
+	}
+	pronoun := "it"
+	if len(names) > 1 {
+		pronoun = "them"
+	}
+	t.Description += fmt.Sprintf("\n\nIf a call leaves out %s, the user is asked for %s.", joinOr(names), pronoun)
+}
+
+// joinOr joins names as "a", "a or b", or "a, b or c".
+func joinOr(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
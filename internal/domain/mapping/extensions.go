@@ -0,0 +1,162 @@
+package mapping
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Operation extensions that override the request defaults of the generated server.
+const (
+	extTimeout     = "x-mcp-timeout"      // milliseconds, or a duration such as "5s"
+	extRetries     = "x-mcp-retries"      // maximum number of retries
+	extIdempotent  = "x-mcp-idempotent"   // retry a non-idempotent method anyway
+	extRateLimit   = "x-ratelimit"        // rate and concurrency limits; also on the document
+	extMaxResponse = "x-mcp-max-response" // characters of a response passed on to the model
+)
+
+// httpOptions reads the x-mcp-* and x-ratelimit extensions of op. Invalid values are
+// ignored with a warning, so the runtime defaults apply.
+func httpOptions(op *model.Operation) (model.MCPToolHTTP, []model.Warning) {
+	var h model.MCPToolHTTP
+	var warnings []model.Warning
+	invalid := func(ext, want string) {
+		warnings = append(warnings, warning(op, "/"+ext, "%s must be %s; the default applies", ext, want))
+	}
+	if v, ok := op.Extensions[extTimeout]; ok {
+		if ms, ok := durationMs(v); ok && ms > 0 {
+			h.TimeoutMs = ms
+		} else {
+			invalid(extTimeout, `a positive number of milliseconds or a duration such as "5s"`)
+		}
+	}
+	if v, ok := op.Extensions[extRetries]; ok {
+		if n, ok := wholeNumber(v); ok && n >= 0 {
+			h.Retries = &n
+		} else {
+			invalid(extRetries, "a non-negative integer")
+		}
+	}
+	if v, ok := op.Extensions[extIdempotent]; ok {
+		if b, ok := v.(bool); ok {
+			h.Idempotent = b
+		} else {
+			invalid(extIdempotent, "a boolean")
+		}
+	}
+	if v, ok := op.Extensions[extMaxResponse]; ok {
+		if n, ok := wholeNumber(v); ok && n > 0 {
+			h.MaxResponse = n
+		} else {
+			invalid(extMaxResponse, "a positive number of characters")
+		}
+	}
+	if v, ok := op.Extensions[extRateLimit]; ok {
+		if rl, problem := rateLimit(v); problem == "" {
+			h.RateLimit = rl
+		} else {
+			warnings = append(warnings, warning(op, "/"+extRateLimit, "%s %s; the operation is only limited globally", extRateLimit, problem))
+		}
+	}
+	return h, warnings
+}
+
+// DocumentRateLimit reads the global limits from the x-ratelimit extension of
+// the document. An invalid value is ignored with a warning.
+func DocumentRateLimit(ext map[string]interface{}) (*model.MCPRateLimit, []model.Warning) {
+	v, ok := ext[extRateLimit]
+	if !ok {
+		return nil, nil
+	}
+	rl, problem := rateLimit(v)
+	if problem != "" {
+		return nil, []model.Warning{{
+			Stage:   "mapping",
+			Pointer: "/" + extRateLimit,
+			Message: fmt.Sprintf("%s %s; the runtime defaults apply", extRateLimit, problem),
+		}}
+	}
+	return rl, nil
+}
+
+// rateLimit decodes an x-ratelimit object such as
+// {limit: 10, window: 1s, burst: 20, concurrency: 4}, or explains why it is invalid.
+func rateLimit(v interface{}) (*model.MCPRateLimit, string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, "must be an object with limit, window, burst or concurrency"
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rl := &model.MCPRateLimit{}
+	for _, k := range keys {
+		if k == "window" {
+			ms, ok := durationMs(obj[k])
+			if !ok || ms <= 0 {
+				return nil, `window must be a positive number of milliseconds or a duration such as "1m"`
+			}
+			rl.WindowMs = ms
+			continue
+		}
+		var field *int
+		switch k {
+		case "limit":
+			field = &rl.Limit
+		case "burst":
+			field = &rl.Burst
+		case "concurrency":
+			field = &rl.Concurrency
+		default:
+			return nil, fmt.Sprintf("has unknown field %q (use limit, window, burst or concurrency)", k)
+		}
+		n, ok := wholeNumber(obj[k])
+		if !ok || n < 0 {
+			return nil, k + " must be a non-negative integer"
+		}
+		*field = n
+	}
+	switch {
+	case rl.Limit == 0 && rl.Concurrency == 0:
+		return nil, "sets neither limit nor concurrency"
+	case rl.Limit == 0 && (rl.Burst > 0 || rl.WindowMs > 0):
+		return nil, "sets window or burst without limit"
+	case rl.Limit > 0 && rl.WindowMs == 0:
+		rl.WindowMs = 1000
+	}
+	return rl, ""
+}
+
+// durationMs converts a number of milliseconds or a Go duration string to
+// whole milliseconds.
+func durationMs(v interface{}) (int, bool) {
+	if s, ok := v.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil || d%time.Millisecond != 0 {
+			return 0, false
+		}
+		return int(d / time.Millisecond), true
+	}
+	return wholeNumber(v)
+}
+
+// wholeNumber converts a decoded JSON number without a fraction to an int.
+func wholeNumber(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		if n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+			return 0, false
+		}
+		return int(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package mapping
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Identity of a server whose spec has no title or no usable version.
+const (
+	DefaultServerName    = "generated-mcp"
+	DefaultServerVersion = "1.0.0"
+)
+
+// ApplyInfo names srv after the spec: the package and server name from
+// info.Title, the version from info.Version and the instructions from
+// info.Description, normalized with PackageName and SemVer.
+func ApplyInfo(srv *model.MCPServer, info model.Info) {
+	srv.Name = PackageName(info.Title)
+	srv.Version = SemVer(info.Version)
+	srv.Instructions = strings.TrimSpace(info.Description)
+}
+
+var (
+	nonPackageChar = regexp.MustCompile(`[^a-z0-9._~-]+`)
+	scopedPackage  = regexp.MustCompile(`^@([^/]+)/(.+)$`)
+)
+
+// PackageName normalizes s to a valid npm package name (which is also used as
+// the MCP server name): lowercase, runs of other characters replaced by a dash,
+// no leading dot or underscore, at most 214 characters. A scope (@scope/name)
+// is kept. It returns DefaultServerName when nothing is left.
+func PackageName(s string) string {
+	if m := scopedPackage.FindStringSubmatch(strings.TrimSpace(s)); m != nil {
+		scope, name := packagePart(m[1]), packagePart(m[2])
+		if scope != "" && name != "" {
+			return truncate("@"+scope+"/"+name, 214)
+		}
+	}
+	name := packagePart(s)
+	if name == "" {
+		return DefaultServerName
+	}
+	return truncate(name, 214)
+}
+
+func packagePart(s string) string {
+	s = nonPackageChar.ReplaceAllString(strings.ToLower(s), "-")
+	s = strings.TrimLeft(s, "-._")
+	return strings.TrimRight(s, "-")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.TrimRight(s[:n], "-._")
+}
+
+var (
+	looseVersion  = regexp.MustCompile(`^[vV]?(\d+)(?:\.(\d+)(?:\.(\d+)((?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?))?)?$`)
+	strictVersion = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// SemVer normalizes v to a semantic version as npm requires: a leading v is
+// dropped and missing minor or patch numbers become 0 (v2 -> 2.0.0); a
+// pre-release or build suffix is only kept after a full version. Versions that
+// still are not valid (e.g. dates) become DefaultServerVersion.
+func SemVer(v string) string {
+	if out, ok := ParseSemVer(v); ok {
+		return out
+	}
+	return DefaultServerVersion
+}
+
+// ParseSemVer is SemVer reporting whether v could be normalized instead of
+// falling back to DefaultServerVersion.
+func ParseSemVer(v string) (string, bool) {
+	m := looseVersion.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return "", false
+	}
+	parts := make([]string, 3)
+	for i, p := range m[1:4] {
+		n, err := strconv.Atoi(p)
+		if p == "" || err != nil {
+			n = 0
+		}
+		parts[i] = strconv.Itoa(n)
+	}
+	out := strings.Join(parts, ".") + m[4]
+	if !strictVersion.MatchString(out) {
+		return "", false
+	}
+	return out, true
+}
@@ -9,8 +9,10 @@ import (
 )
 
 // OperationToMCPTool converts one OpenAPI operation to one MCP tool.
-// Tool name: operationId if present, else sanitized path+method (e.g. get_users).
+// Tool name: operationId if present, else sanitized path+method (e.g. get_users),
+// prefixed with the source prefix when set.
 // InputSchema: properties from parameters + requestBody; required array.
+// The source base URL, when present, takes precedence over baseURL.
 func OperationToMCPTool(op *model.Operation, baseURL string) *model.MCPTool {
 	name := toolName(op)
 	desc := op.Summary
@@ -26,6 +28,7 @@ func OperationToMCPTool(op *model.Operation, baseURL string) *model.MCPTool {
 			In:       p.In,
 			Required: p.Required,
 			Schema:   p.Schema,
+			Pointer:  p.Pointer,
 		})
 	}
 
@@ -37,7 +40,29 @@ func OperationToMCPTool(op *model.Operation, baseURL string) *model.MCPTool {
 		}
 	}
 
-	return &model.MCPTool{
+	source := ""
+	if op.Source != nil {
+		source = op.Source.Name
+		if op.Source.BaseURL != "" {
+			baseURL = op.Source.BaseURL
+		}
+	}
+
+	warnings := shadowedBodyWarnings(op)
+	http, w := httpOptions(op)
+	warnings = append(warnings, w...)
+	paging, w := pagination(op)
+	warnings = append(warnings, w...)
+	async, w := asyncOptions(op)
+	warnings = append(warnings, w...)
+	if async != nil {
+		desc += "\n\nThe operation may run in the background: the tool waits until it finishes and reports progress meanwhile."
+	}
+	stream := streamFormat(op.ContentType)
+	if stream != "" {
+		desc += "\n\nThe response is a stream of events: the tool collects them until the stream ends or a time or size limit is reached."
+	}
+	t := &model.MCPTool{
 		Name:        name,
 		Description: desc,
 		InputSchema: schema,
@@ -46,13 +71,73 @@ func OperationToMCPTool(op *model.Operation, baseURL string) *model.MCPTool {
 		Method:      strings.ToUpper(op.Method),
 		Path:        op.Path,
 		BaseURL:     baseURL,
+		Source:      source,
+		Annotations: annotations(op.Method),
+		HTTP:        http,
+		Errors:      op.Errors,
+		Response:    op.Response,
+		Async:       async,
+		ContentType: op.ContentType,
+		Stream:      stream,
+		Warnings:    warnings,
+	}
+	if paging != nil {
+		enablePagination(t, paging)
+	}
+	return t
+}
+
+// annotations derives the tool hints from HTTP method semantics: safe methods
+// are read-only, PUT, PATCH and DELETE may overwrite or delete data, and only
+// POST and PATCH are non-idempotent. Every tool calls an external API.
+func annotations(method string) *model.MCPToolAnnotations {
+	a := &model.MCPToolAnnotations{OpenWorldHint: true}
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		a.ReadOnlyHint = true
+		a.IdempotentHint = true
+	case "PUT", "DELETE":
+		a.DestructiveHint = true
+		a.IdempotentHint = true
+	case "PATCH":
+		a.DestructiveHint = true
+	}
+	return a
+}
+
+// shadowedBodyWarnings reports request body properties that share a name with
+// a path or query parameter: tool arguments are flat, so only the parameter is sent.
+func shadowedBodyWarnings(op *model.Operation) []model.Warning {
+	if op.RequestBody == nil {
+		return nil
+	}
+	props, _ := op.RequestBody.Schema["properties"].(map[string]interface{})
+	var out []model.Warning
+	for _, p := range op.Parameters {
+		if _, ok := props[p.Name]; ok && (p.In == "path" || p.In == "query") {
+			out = append(out, warning(op, "/requestBody/content/application~1json/schema/properties/"+model.PointerEscape(p.Name),
+				"request body property %q has the same name as a %s parameter; only the parameter is sent", p.Name, p.In))
+		}
+	}
+	return out
+}
+
+// warning returns a mapping warning for op; pointer is relative to the operation.
+func warning(op *model.Operation, pointer, format string, args ...interface{}) model.Warning {
+	return model.Warning{
+		Stage:     "mapping",
+		Source:    sourceName(op),
+		Operation: strings.ToUpper(op.Method) + " " + op.Path,
+		Pointer:   model.OperationPointer(op.Path, op.Method) + pointer,
+		Message:   fmt.Sprintf(format, args...),
 	}
 }
 
 // OperationsToMCPTools maps each operation to one MCP tool, ensuring unique
 // and descriptive tool names. It detects auto-generated numeric suffixes
 // (e.g. create_1, updateById_1) and name collisions, falling back to
-// path-based naming for disambiguation.
+// path-based naming for disambiguation. When operations from several specs
+// still collide, the source name is used to qualify them.
 func OperationsToMCPTools(ops []*model.Operation, baseURL string) []*model.MCPTool {
 	tools := make([]*model.MCPTool, 0, len(ops))
 
@@ -72,7 +157,23 @@ func OperationsToMCPTools(ops []*model.Operation, baseURL string) []*model.MCPTo
 		}
 	}
 
-	// Pass 3: final dedup — if collisions remain, append _2, _3, etc.
+	// Pass 3: names still colliding across specs are qualified with the
+	// source name (unprefixed sources only; prefixed names are already qualified).
+	nameSources := make(map[string]map[string]bool)
+	for i, t := range tools {
+		if nameSources[t.Name] == nil {
+			nameSources[t.Name] = make(map[string]bool)
+		}
+		nameSources[t.Name][sourceName(ops[i])] = true
+	}
+	for i, t := range tools {
+		src := ops[i].Source
+		if len(nameSources[t.Name]) > 1 && src != nil && src.Prefix == "" {
+			tools[i].Name = sanitizeName(src.Name) + "_" + t.Name
+		}
+	}
+
+	// Pass 4: final dedup — if collisions remain, append _2, _3, etc.
 	seen := make(map[string]int)
 	for i, t := range tools {
 		seen[t.Name]++
@@ -81,6 +182,17 @@ func OperationsToMCPTools(ops []*model.Operation, baseURL string) []*model.MCPTo
 		}
 	}
 
+	for i, t := range tools {
+		if natural := toolName(ops[i]); t.Name != natural {
+			pointer := ""
+			if ops[i].OperationID != "" {
+				pointer = "/operationId"
+			}
+			t.Warnings = append(t.Warnings, warning(ops[i], pointer,
+				"tool name %q collides or has a numeric suffix; renamed to %q", natural, t.Name))
+		}
+	}
+
 	return tools
 }
 
@@ -96,22 +208,33 @@ func pathBasedName(op *model.Operation) string {
 	pathPart := strings.Trim(pathToName(op.Path), "_")
 	methodPart := strings.ToLower(op.Method)
 	if pathPart == "" {
-		return methodPart
+		return withPrefix(op, methodPart)
 	}
-	return methodPart + "_" + pathPart
+	return withPrefix(op, methodPart+"_"+pathPart)
 }
 
 func toolName(op *model.Operation) string {
 	if op.OperationID != "" {
-		return sanitizeName(op.OperationID)
+		return withPrefix(op, sanitizeName(op.OperationID))
 	}
 	// path + method: e.g. /users -> get_users
-	pathPart := strings.Trim(pathToName(op.Path), "_")
-	methodPart := strings.ToLower(op.Method)
-	if pathPart == "" {
-		return methodPart
+	return pathBasedName(op)
+}
+
+// withPrefix prepends the source prefix, if any, to name.
+func withPrefix(op *model.Operation, name string) string {
+	if op.Source == nil || op.Source.Prefix == "" {
+		return name
+	}
+	return sanitizeName(op.Source.Prefix) + "_" + name
+}
+
+// sourceName returns the name of op's source, or "" for a single spec.
+func sourceName(op *model.Operation) string {
+	if op.Source == nil {
+		return ""
 	}
-	return methodPart + "_" + pathPart
+	return op.Source.Name
 }
 
 func pathToName(path string) string {
@@ -0,0 +1,292 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Tool arguments of paginated tools; they are not sent to the API.
+const (
+	FetchAllArgument = "fetchAll"
+	MaxPagesArgument = "maxPages"
+)
+
+// extPagination describes the pagination of an operation (an object with
+// style, param, sizeParam, start, items and nextCursor), or turns detection
+// off (false).
+const extPagination = "x-mcp-pagination"
+
+// Query parameter and response property names recognized by detection.
+var (
+	pageParams       = []string{"page", "page_number", "pageNumber", "pageNum"}
+	offsetParams     = []string{"offset", "skip"}
+	cursorParams     = []string{"cursor", "page_token", "pageToken", "next_token", "nextToken", "starting_after", "after", "continuation_token", "continuationToken"}
+	sizeParams       = []string{"limit", "per_page", "perPage", "page_size", "pageSize", "size", "count", "max_results", "maxResults"}
+	itemProperties   = []string{"data", "items", "results", "records", "entries", "values"}
+	cursorProperties = []string{"next_cursor", "nextCursor", "next_page_token", "nextPageToken", "next_token", "nextToken", "cursor", "next"}
+)
+
+var paginationStyles = map[string]bool{"page": true, "offset": true, "cursor": true, "link": true}
+
+// pagination returns how op pages through its results: from its
+// x-mcp-pagination extension, completed by detection, or detected from the
+// query parameters, the response schema and a Link response header. Only GET
+// operations whose response has an item array are paginated. Problems with
+// the extension are returned as warnings.
+func pagination(op *model.Operation) (*model.MCPPagination, []model.Warning) {
+	v, explicit := op.Extensions[extPagination]
+	if b, ok := v.(bool); explicit && ok && !b {
+		return nil, nil
+	}
+	fail := func(format string, args ...interface{}) (*model.MCPPagination, []model.Warning) {
+		if !explicit {
+			return nil, nil
+		}
+		return nil, []model.Warning{warning(op, "/"+extPagination,
+			"%s: %s; the tool fetches one page", extPagination, fmt.Sprintf(format, args...))}
+	}
+
+	p := detectPagination(op)
+	if explicit {
+		cfg, ok := v.(map[string]interface{})
+		if !ok {
+			return fail("must be false or an object with style, param, sizeParam, start, items and nextCursor")
+		}
+		if style, ok := cfg["style"].(string); ok && (p == nil || p.Style != style) {
+			items, _ := itemArray(op.Response)
+			p = &model.MCPPagination{Style: style, SizeParam: firstQueryParam(op, sizeParams), Start: 1, Items: items}
+		}
+		if p == nil {
+			return fail("style is required: page, offset, cursor or link")
+		}
+		for _, f := range []struct {
+			key string
+			dst *string
+		}{{"param", &p.Param}, {"sizeParam", &p.SizeParam}, {"items", &p.Items}, {"nextCursor", &p.NextCursor}} {
+			if raw, ok := cfg[f.key]; ok {
+				s, ok := raw.(string)
+				if !ok {
+					return fail("%s must be a string", f.key)
+				}
+				*f.dst = s
+			}
+		}
+		if raw, ok := cfg["start"]; ok {
+			n, ok := wholeNumber(raw)
+			if !ok || n < 0 {
+				return fail("start must be a non-negative integer")
+			}
+			p.Start = n
+		}
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	switch {
+	case !paginationStyles[p.Style]:
+		return fail("unknown style %q; want page, offset, cursor or link", p.Style)
+	case !strings.EqualFold(op.Method, "GET"):
+		return fail("only GET operations are paginated")
+	case p.Style != "link" && !hasQueryParam(op, p.Param):
+		return fail("%q is not a query parameter of the operation", p.Param)
+	case p.SizeParam != "" && !hasQueryParam(op, p.SizeParam):
+		return fail("%q is not a query parameter of the operation", p.SizeParam)
+	case p.Style == "cursor" && p.NextCursor == "":
+		return fail("the response declares no next cursor; set nextCursor")
+	case pagingClash(op) != "":
+		return fail("%s", pagingClash(op))
+	}
+	if items, ok := schemaAt(op.Response, p.Items); !ok || items["type"] != "array" {
+		if p.Items == "" {
+			return fail("the response has no item array; set items")
+		}
+		return fail("items %q is not an array in the response schema", p.Items)
+	}
+	return p, nil
+}
+
+// detectPagination recognizes the common pagination parameters, or returns nil.
+func detectPagination(op *model.Operation) *model.MCPPagination {
+	if !strings.EqualFold(op.Method, "GET") {
+		return nil
+	}
+	items, ok := itemArray(op.Response)
+	if !ok {
+		return nil
+	}
+	p := &model.MCPPagination{Items: items, SizeParam: firstQueryParam(op, sizeParams), Start: 1}
+	hasLink := false
+	for _, h := range op.Headers {
+		hasLink = hasLink || strings.EqualFold(h, "Link")
+	}
+	switch {
+	case firstQueryParam(op, cursorParams) != "":
+		p.Style, p.Param = "cursor", firstQueryParam(op, cursorParams)
+		p.NextCursor = nextCursorPath(op.Response, items)
+		if p.NextCursor == "" && hasLink {
+			p.Style, p.Param = "link", ""
+		}
+	case firstQueryParam(op, pageParams) != "":
+		p.Style, p.Param = "page", firstQueryParam(op, pageParams)
+		for _, param := range op.Parameters {
+			if param.Name != p.Param {
+				continue
+			}
+			if n, ok := wholeNumber(param.Schema["default"]); ok && n >= 0 {
+				p.Start = n
+			} else if n, ok := wholeNumber(param.Schema["minimum"]); ok && n >= 0 {
+				p.Start = n
+			}
+		}
+	case firstQueryParam(op, offsetParams) != "" && p.SizeParam != "":
+		p.Style, p.Param = "offset", firstQueryParam(op, offsetParams)
+	case hasLink:
+		p.Style = "link"
+	default:
+		return nil
+	}
+	return p
+}
+
+// itemArray returns the path of the item array in a response schema: the
+// response itself, a property with a usual name, or the only array property.
+func itemArray(schema map[string]interface{}) (string, bool) {
+	if schema["type"] == "array" {
+		return "", true
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	var arrays []string
+	for name, raw := range props {
+		if prop, _ := raw.(map[string]interface{}); prop["type"] == "array" {
+			arrays = append(arrays, name)
+		}
+	}
+	for _, name := range itemProperties {
+		for _, a := range arrays {
+			if a == name {
+				return a, true
+			}
+		}
+	}
+	if len(arrays) == 1 {
+		return arrays[0], true
+	}
+	return "", false
+}
+
+// nextCursorPath looks for the next cursor among the properties of the
+// response and of its object properties (such as meta or pagination), other
+// than the item array. It returns "" if there is none.
+func nextCursorPath(schema map[string]interface{}, items string) string {
+	props, _ := schema["properties"].(map[string]interface{})
+	if name := cursorProperty(props); name != "" {
+		return name
+	}
+	parents := make([]string, 0, len(props))
+	for name := range props {
+		parents = append(parents, name)
+	}
+	sort.Strings(parents)
+	for _, parent := range parents {
+		obj, _ := props[parent].(map[string]interface{})
+		if parent == items || obj["type"] != "object" {
+			continue
+		}
+		nested, _ := obj["properties"].(map[string]interface{})
+		if name := cursorProperty(nested); name != "" {
+			return parent + "." + name
+		}
+	}
+	return ""
+}
+
+// cursorProperty returns the first scalar property of props with a usual next cursor name.
+func cursorProperty(props map[string]interface{}) string {
+	for _, name := range cursorProperties {
+		if prop, _ := props[name].(map[string]interface{}); prop != nil && prop["type"] != "object" && prop["type"] != "array" {
+			return name
+		}
+	}
+	return ""
+}
+
+// schemaAt returns the schema at a dotted property path of schema; "" is schema itself.
+func schemaAt(schema map[string]interface{}, path string) (map[string]interface{}, bool) {
+	if path == "" {
+		return schema, schema != nil
+	}
+	for _, name := range strings.Split(path, ".") {
+		props, _ := schema["properties"].(map[string]interface{})
+		next, ok := props[name].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		schema = next
+	}
+	return schema, true
+}
+
+// pagingClash explains which parameter or request body property of op is
+// named like a pagination argument, or returns "".
+func pagingClash(op *model.Operation) string {
+	for _, name := range []string{FetchAllArgument, MaxPagesArgument} {
+		for _, p := range op.Parameters {
+			if p.Name == name {
+				return fmt.Sprintf("the operation already has a %s parameter named %q", p.In, name)
+			}
+		}
+		if op.RequestBody != nil {
+			if props, _ := op.RequestBody.Schema["properties"].(map[string]interface{}); props[name] != nil {
+				return fmt.Sprintf("the request body already has a property named %q", name)
+			}
+		}
+	}
+	return ""
+}
+
+func firstQueryParam(op *model.Operation, names []string) string {
+	for _, name := range names {
+		if hasQueryParam(op, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+func hasQueryParam(op *model.Operation, name string) bool {
+	for _, p := range op.Parameters {
+		if p.In == "query" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// enablePagination adds the fetchAll and maxPages arguments to t and says how
+// to use them in its description.
+func enablePagination(t *model.MCPTool, p *model.MCPPagination) {
+	t.Pagination = p
+	t.Description += fmt.Sprintf("\n\nResults are paginated. Set %s to fetch that many pages, or %s to fetch them all, merged into one response.",
+		MaxPagesArgument, FetchAllArgument)
+	props, _ := t.InputSchema["properties"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+		if t.InputSchema == nil {
+			t.InputSchema = map[string]interface{}{"type": "object"}
+		}
+		t.InputSchema["properties"] = props
+	}
+	props[FetchAllArgument] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Fetch every page and merge the results",
+	}
+	props[MaxPagesArgument] = map[string]interface{}{
+		"type":        "integer",
+		"minimum":     1,
+		"description": "Number of pages to fetch and merge",
+	}
+}
@@ -0,0 +1,130 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// ProjectionArgument is the tool argument selecting response fields.
+const ProjectionArgument = "fields"
+
+// extProjection turns the fields argument on (true) or off (false) for one
+// operation, whatever the default.
+const extProjection = "x-mcp-projection"
+
+// maxListedFields caps the field paths listed in a tool description.
+const maxListedFields = 40
+
+// ApplyProjection adds the fields argument to the tools whose response is
+// JSON: to all of them when all is set, and to those whose operation sets
+// x-mcp-projection: true; x-mcp-projection: false opts an operation out.
+// tools and ops are parallel, as passed to and returned by
+// OperationsToMCPTools. Problems are added to the warnings of the tool.
+func ApplyProjection(tools []*model.MCPTool, ops []*model.Operation, all bool) {
+	for i, t := range tools {
+		op := ops[i]
+		enable, explicit := all, false
+		if v, ok := op.Extensions[extProjection]; ok {
+			b, isBool := v.(bool)
+			if !isBool {
+				t.Warnings = append(t.Warnings, warning(op, "/"+extProjection, "%s must be a boolean; the default applies", extProjection))
+			} else {
+				enable, explicit = b, true
+			}
+		}
+		if !enable {
+			continue
+		}
+		if problem := projectionProblem(t); problem != "" {
+			if explicit {
+				t.Warnings = append(t.Warnings, warning(op, "/"+extProjection, "%s: %s; the tool has no fields argument", extProjection, problem))
+			}
+			continue
+		}
+		enableProjection(t)
+	}
+}
+
+// projectionProblem explains why t cannot get a fields argument, or returns "".
+func projectionProblem(t *model.MCPTool) string {
+	if t.Response == nil {
+		return "the operation has no JSON response schema"
+	}
+	for _, p := range t.Params {
+		if p.Name == ProjectionArgument {
+			return fmt.Sprintf("the operation already has a %s parameter named %q", p.In, ProjectionArgument)
+		}
+	}
+	if t.Body != nil {
+		if props, _ := t.Body.Schema["properties"].(map[string]interface{}); props[ProjectionArgument] != nil {
+			return fmt.Sprintf("the request body already has a property named %q", ProjectionArgument)
+		}
+	}
+	if len(responseFields(t.Response, "", 0)) == 0 {
+		return "the response schema declares no properties"
+	}
+	return ""
+}
+
+// enableProjection adds the optional fields argument to t and documents the
+// paths it accepts in the tool description.
+func enableProjection(t *model.MCPTool) {
+	t.Fields = responseFields(t.Response, "", 0)
+	listed := t.Fields
+	more := ""
+	if len(listed) > maxListedFields {
+		listed, more = listed[:maxListedFields], ", ..."
+	}
+	example := strings.Join(t.Fields[:min(2, len(t.Fields))], ",")
+	t.Description += fmt.Sprintf("\n\nSet %s to a comma-separated list of response fields to return only those, e.g. %q. Fields: %s%s.",
+		ProjectionArgument, example, strings.Join(listed, ", "), more)
+
+	props, _ := t.InputSchema["properties"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+		if t.InputSchema == nil {
+			t.InputSchema = map[string]interface{}{"type": "object"}
+		}
+		t.InputSchema["properties"] = props
+	}
+	props[ProjectionArgument] = map[string]interface{}{
+		"type":        "string",
+		"description": "Comma-separated response fields to return, e.g. " + example,
+	}
+}
+
+// responseFields lists the property paths of schema, three levels deep:
+// nested objects are joined with dots and array items marked with [], e.g.
+// id, items[].sku. A top-level array lists the fields of its items.
+func responseFields(schema map[string]interface{}, prefix string, depth int) []string {
+	if items, ok := schema["items"].(map[string]interface{}); ok && schema["type"] == "array" {
+		if prefix != "" {
+			prefix += "[]"
+		}
+		return responseFields(items, prefix, depth)
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	if depth >= 3 || len(props) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out []string
+	for _, name := range names {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		out = append(out, path)
+		if prop, ok := props[name].(map[string]interface{}); ok {
+			out = append(out, responseFields(prop, path, depth+1)...)
+		}
+	}
+	return out
+}
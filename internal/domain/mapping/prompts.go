@@ -0,0 +1,227 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// OperationsToMCPPrompts builds prompts that guide agents through multi-step
+// API usage: one prompt per tag summarizing its tools and resources, and one
+// prompt per response link describing the call sequence. ops are all the
+// operations of the spec; the ones exposed as resources are matched to
+// resources (as returned by OperationsToMCPResources) by source and path, and
+// tools are the tools mapped from the others, in order.
+func OperationsToMCPPrompts(ops []*model.Operation, tools []*model.MCPTool, resources []*model.MCPResource, tags []model.Tag) []*model.MCPPrompt {
+	steps := promptSteps(ops, tools, resources)
+	var prompts []*model.MCPPrompt
+	prompts = append(prompts, tagPrompts(ops, steps, tags)...)
+	prompts = append(prompts, linkPrompts(ops, steps)...)
+
+	seen := make(map[string]int)
+	for _, p := range prompts {
+		seen[p.Name]++
+		if seen[p.Name] > 1 {
+			p.Name = fmt.Sprintf("%s_%d", p.Name, seen[p.Name])
+		}
+	}
+	return prompts
+}
+
+// promptStep is what one operation became: a tool, or a resource.
+type promptStep struct {
+	tool     *model.MCPTool
+	resource *model.MCPResource
+}
+
+// promptSteps returns the step of each operation in ops, or a zero step when
+// there are fewer tools than operations left.
+func promptSteps(ops []*model.Operation, tools []*model.MCPTool, resources []*model.MCPResource) []promptStep {
+	byPath := make(map[string]*model.MCPResource, len(resources))
+	for _, r := range resources {
+		byPath[r.Source+" "+r.Path] = r
+	}
+	steps := make([]promptStep, len(ops))
+	next := 0
+	for i, op := range ops {
+		if r := byPath[sourceName(op)+" "+op.Path]; r != nil && strings.EqualFold(op.Method, "GET") {
+			steps[i].resource = r
+		} else if next < len(tools) {
+			steps[i].tool = tools[next]
+			next++
+		}
+	}
+	return steps
+}
+
+func (s promptStep) ok() bool { return s.tool != nil || s.resource != nil }
+
+func (s promptStep) name() string {
+	if s.resource != nil {
+		return s.resource.Name
+	}
+	return s.tool.Name
+}
+
+// action tells the agent how to take the step, e.g. "call get_order (GET
+// /orders/{id})" or "read the resource api://orders/{id}".
+func (s promptStep) action() string {
+	if s.resource != nil {
+		return "read the resource " + s.resource.URI
+	}
+	return fmt.Sprintf("call %s (%s %s)", s.tool.Name, s.tool.Method, s.tool.Path)
+}
+
+// tagPrompts returns one prompt per tag listing the tools and resources
+// tagged with it.
+func tagPrompts(ops []*model.Operation, steps []promptStep, tags []model.Tag) []*model.MCPPrompt {
+	byTag := make(map[string][]promptStep)
+	for i, op := range ops {
+		if !steps[i].ok() {
+			continue
+		}
+		for _, tag := range op.Tags {
+			byTag[tag] = append(byTag[tag], steps[i])
+		}
+	}
+	tagDesc := make(map[string]string)
+	for _, t := range tags {
+		tagDesc[t.Name] = t.Description
+	}
+
+	names := make([]string, 0, len(byTag))
+	for name := range byTag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []*model.MCPPrompt
+	for _, tag := range names {
+		var b strings.Builder
+		if d := tagDesc[tag]; d != "" {
+			fmt.Fprintf(&b, "%s\n\n", d)
+		}
+		fmt.Fprintf(&b, "Use the following tools to work with %s:\n", tag)
+		for _, st := range byTag[tag] {
+			if r := st.resource; r != nil {
+				fmt.Fprintf(&b, "- resource %s (%s): %s\n", r.URI, r.Name, r.Description)
+			} else {
+				fmt.Fprintf(&b, "- %s (%s %s): %s\n", st.tool.Name, st.tool.Method, st.tool.Path, st.tool.Description)
+			}
+		}
+		out = append(out, &model.MCPPrompt{
+			Name:        sanitizeName(tag) + "_tools",
+			Description: fmt.Sprintf("Overview of the tools for %s", tag),
+			Text:        strings.TrimRight(b.String(), "\n"),
+		})
+	}
+	return out
+}
+
+// linkPrompts returns one prompt per response link between operations that
+// were mapped to a tool or a resource.
+func linkPrompts(ops []*model.Operation, steps []promptStep) []*model.MCPPrompt {
+	var out []*model.MCPPrompt
+	for i, op := range ops {
+		for _, l := range op.Links {
+			j := linkTarget(ops, l)
+			if j < 0 || !steps[i].ok() || !steps[j].ok() {
+				continue
+			}
+			src, dst := steps[i], steps[j]
+			desc := l.Description
+			if desc == "" {
+				desc = fmt.Sprintf("Call %s, then %s with values from its response", src.name(), dst.name())
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "1. %s.\n", capitalize(src.action()))
+			fmt.Fprintf(&b, "2. When it responds with status %s, %s", l.Status, dst.action())
+			if len(l.Parameters) == 0 {
+				b.WriteString(".")
+			} else {
+				if dst.resource != nil {
+					b.WriteString(", filling in:")
+				} else {
+					b.WriteString(", passing:")
+				}
+				names := make([]string, 0, len(l.Parameters))
+				for name := range l.Parameters {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					fmt.Fprintf(&b, "\n   - %s: %s", name, describeExpression(l.Parameters[name]))
+				}
+			}
+			out = append(out, &model.MCPPrompt{
+				Name:        src.name() + "_then_" + dst.name(),
+				Description: desc,
+				Text:        b.String(),
+			})
+		}
+	}
+	return out
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// linkTarget returns the index of the operation l points to, or -1.
+// Targets are resolved by operationId, or by a local operationRef of the form
+// #/paths/<escaped path>/<method>.
+func linkTarget(ops []*model.Operation, l model.Link) int {
+	if l.OperationID != "" {
+		for i, op := range ops {
+			if op.OperationID == l.OperationID {
+				return i
+			}
+		}
+		return -1
+	}
+	ref := strings.TrimPrefix(l.OperationRef, "#/paths/")
+	if ref == l.OperationRef {
+		return -1
+	}
+	slash := strings.LastIndex(ref, "/")
+	if slash < 0 {
+		return -1
+	}
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(ref[:slash])
+	method := ref[slash+1:]
+	for i, op := range ops {
+		if op.Path == path && strings.EqualFold(op.Method, method) {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeExpression turns an OpenAPI runtime expression into a short
+// instruction for the agent; unknown forms are returned verbatim.
+func describeExpression(expr string) string {
+	switch {
+	case strings.HasPrefix(expr, "$response.body#/"):
+		field := strings.ReplaceAll(strings.TrimPrefix(expr, "$response.body#/"), "/", ".")
+		return fmt.Sprintf("the %q field of the first response body", field)
+	case expr == "$response.body":
+		return "the first response body"
+	case strings.HasPrefix(expr, "$response.header."):
+		return fmt.Sprintf("the %q response header of the first call", strings.TrimPrefix(expr, "$response.header."))
+	case strings.HasPrefix(expr, "$request.path."):
+		return fmt.Sprintf("the same %q path argument used in the first call", strings.TrimPrefix(expr, "$request.path."))
+	case strings.HasPrefix(expr, "$request.query."):
+		return fmt.Sprintf("the same %q query argument used in the first call", strings.TrimPrefix(expr, "$request.query."))
+	case strings.HasPrefix(expr, "$request.body#/"):
+		field := strings.ReplaceAll(strings.TrimPrefix(expr, "$request.body#/"), "/", ".")
+		return fmt.Sprintf("the same %q field sent in the first request body", field)
+	default:
+		return expr
+	}
+}
@@ -0,0 +1,93 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+
+	"bakemcp/internal/domain/model"
+)
+
+// resourceScheme is the URI scheme used for generated MCP resources.
+const resourceScheme = "api"
+
+// OperationToMCPResource converts a read-only GET operation to an MCP resource.
+// Parameterless GETs become plain resources (api://products); GETs whose only
+// parameters are path parameters become resource templates
+// (api://products/{productId}). Returns nil for any other operation.
+func OperationToMCPResource(op *model.Operation, baseURL string) *model.MCPResource {
+	if !strings.EqualFold(op.Method, "GET") || op.RequestBody != nil {
+		return nil
+	}
+	var params []model.MCPToolParam
+	for _, p := range op.Parameters {
+		if p.In != "path" {
+			return nil
+		}
+		params = append(params, model.MCPToolParam{
+			Name:     p.Name,
+			In:       p.In,
+			Required: true,
+			Schema:   p.Schema,
+			Pointer:  p.Pointer,
+		})
+	}
+	desc := op.Summary
+	if desc == "" {
+		desc = fmt.Sprintf("%s %s", strings.ToUpper(op.Method), op.Path)
+	}
+	source := sourceName(op)
+	if op.Source != nil && op.Source.BaseURL != "" {
+		baseURL = op.Source.BaseURL
+	}
+	http, warnings := httpOptions(op)
+	return &model.MCPResource{
+		Name:        toolName(op),
+		Description: desc,
+		URI:         resourceURI(source, op.Path),
+		MimeType:    "application/json",
+		Params:      params,
+		Path:        op.Path,
+		BaseURL:     baseURL,
+		Source:      source,
+		HTTP:        http,
+		Errors:      op.Errors,
+		Warnings:    warnings,
+	}
+}
+
+// OperationsToMCPResources splits ops into MCP resources and the operations
+// that remain tools. Resource names are deduplicated the same way tool names are.
+func OperationsToMCPResources(ops []*model.Operation, baseURL string) ([]*model.MCPResource, []*model.Operation) {
+	var resources []*model.MCPResource
+	var rest []*model.Operation
+	for _, op := range ops {
+		if r := OperationToMCPResource(op, baseURL); r != nil {
+			resources = append(resources, r)
+			continue
+		}
+		rest = append(rest, op)
+	}
+
+	seen := make(map[string]int)
+	for _, r := range resources {
+		seen[r.Name]++
+		if seen[r.Name] > 1 {
+			r.Name = fmt.Sprintf("%s_%d", r.Name, seen[r.Name])
+		}
+	}
+	return resources, rest
+}
+
+// resourceURI builds the resource URI from an API path, keeping {param}
+// placeholders so they double as URI template variables. Resources from a
+// named source are namespaced under it (api://billing/invoices).
+func resourceURI(source, path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		path = "root"
+	}
+	if source != "" {
+		path = sanitizeName(source) + "/" + path
+	}
+	return resourceScheme + "://" + path
+}
@@ -0,0 +1,16 @@
+package mapping
+
+import "strings"
+
+// streamFormat returns how the generated tool reads a response of
+// contentType as it arrives: "sse" for server-sent events, "ndjson" for
+// newline-delimited JSON, or "" for a response read whole.
+func streamFormat(contentType string) string {
+	switch strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])) {
+	case "text/event-stream":
+		return "sse"
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines", "application/jsonlines":
+		return "ndjson"
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+
+	"bakemcp/internal/domain/arazzo"
+	"bakemcp/internal/domain/model"
+)
+
+// WorkflowsToMCPWorkflows resolves each Arazzo workflow's steps against ops and
+// returns one composite tool per workflow. Names are derived from workflowId
+// and made unique against the already mapped tools. Returns an error when a
+// step references an unknown operationId, one that several operations share
+// (e.g. across merged specs), or uses an unsupported criterion.
+func WorkflowsToMCPWorkflows(wfs []arazzo.Workflow, ops []*model.Operation, tools []*model.MCPTool, baseURL string) ([]*model.MCPWorkflow, error) {
+	byID := make(map[string][]*model.Operation)
+	for _, op := range ops {
+		if op.OperationID != "" {
+			byID[op.OperationID] = append(byID[op.OperationID], op)
+		}
+	}
+	taken := make(map[string]bool)
+	for _, t := range tools {
+		taken[t.Name] = true
+	}
+
+	out := make([]*model.MCPWorkflow, 0, len(wfs))
+	for _, wf := range wfs {
+		w := &model.MCPWorkflow{
+			Name:        uniqueName(sanitizeName(wf.WorkflowID), taken),
+			Description: wf.Summary,
+			InputSchema: wf.Inputs,
+			Outputs:     wf.Outputs,
+		}
+		if w.Description == "" {
+			w.Description = wf.Description
+		}
+		if w.Description == "" {
+			w.Description = fmt.Sprintf("Run the %s workflow", wf.WorkflowID)
+		}
+		for _, st := range wf.Steps {
+			matches := byID[st.OperationID]
+			switch {
+			case len(matches) == 0:
+				return nil, fmt.Errorf("workflow %s: step %s: unknown operationId %q", wf.WorkflowID, st.StepID, st.OperationID)
+			case len(matches) > 1:
+				return nil, fmt.Errorf("workflow %s: step %s: operationId %q is ambiguous: it appears in %s", wf.WorkflowID, st.StepID, st.OperationID, operationList(matches))
+			}
+			op := matches[0]
+			step, err := workflowStep(st, op, baseURL)
+			if err != nil {
+				return nil, fmt.Errorf("workflow %s: step %s: %w", wf.WorkflowID, st.StepID, err)
+			}
+			w.Steps = append(w.Steps, step)
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+func workflowStep(st arazzo.Step, op *model.Operation, baseURL string) (model.MCPWorkflowStep, error) {
+	step := model.MCPWorkflowStep{
+		ID:      st.StepID,
+		Tool:    OperationToMCPTool(op, baseURL),
+		Outputs: st.Outputs,
+	}
+	for _, p := range st.Parameters {
+		in := p.In
+		if in == "" {
+			in = paramLocation(op, p.Name)
+		}
+		step.Params = append(step.Params, model.MCPWorkflowParam{Name: p.Name, In: in, Value: p.Value})
+	}
+	if st.RequestBody != nil {
+		step.Body = st.RequestBody.Payload
+	}
+	for _, c := range st.SuccessCriteria {
+		if c.Type != "" && c.Type != "simple" {
+			return step, fmt.Errorf("unsupported success criterion type %q", c.Type)
+		}
+		step.SuccessCriteria = append(step.SuccessCriteria, c.Condition)
+	}
+	return step, nil
+}
+
+// operationList describes ops for an error, e.g. "GET /orders (shop) and GET
+// /orders (billing)".
+func operationList(ops []*model.Operation) string {
+	parts := make([]string, len(ops))
+	for i, op := range ops {
+		parts[i] = strings.ToUpper(op.Method) + " " + op.Path
+		if name := sourceName(op); name != "" {
+			parts[i] += " (" + name + ")"
+		}
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// paramLocation returns where op declares the named parameter, defaulting to query.
+func paramLocation(op *model.Operation, name string) string {
+	for _, p := range op.Parameters {
+		if p.Name == name {
+			return p.In
+		}
+	}
+	return "query"
+}
+
+// uniqueName returns name, or name with the first free _2, _3, ... suffix,
+// and marks the result as taken.
+func uniqueName(name string, taken map[string]bool) string {
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	taken[candidate] = true
+	return candidate
+}
@@ -1,13 +1,73 @@
 package model
 
+import "strings"
+
 // Operation represents an OpenAPI operation (path + method) for mapping.
 type Operation struct {
 	Path        string
 	Method      string
 	OperationID string
 	Summary     string
+	Tags        []string
 	Parameters  []Parameter
 	RequestBody *RequestBody
+	Links       []Link                 // Response links to follow-up operations
+	Errors      map[string]string      // Description of each documented non-2xx response, by status (404, 4XX or default)
+	Response    map[string]interface{} // JSON Schema of the first 2xx response with JSON content; nil if none
+	ContentType string                 // Content type of the first 2xx response with content, JSON preferred; "" if none
+	Headers     []string               // Headers declared on the 2xx responses (e.g. Link), sorted
+	Accepted    bool                   // A 202 Accepted response is documented: the operation may finish later
+	Extensions  map[string]interface{} // Specification extensions (x-*) of the operation
+	Source      *Source                // Originating spec when several specs are merged; nil for a single spec
+}
+
+// Source identifies one of several OpenAPI documents merged into a single server.
+type Source struct {
+	Name    string // Identifier for the generated <NAME>_BASE_URL env var
+	Prefix  string // Optional tool name prefix
+	BaseURL string // First server URL of the source spec
+}
+
+// EnvName returns a source name as the prefix of its environment variables
+// (and JS constants): uppercase, with runs of other characters replaced by an
+// underscore, and an underscore first when it would start with a digit
+// (2024-api becomes _2024_API). Names differing only in punctuation or case
+// share an EnvName.
+func EnvName(source string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToUpper(source) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			underscore = false
+			b.WriteRune(r)
+		} else {
+			underscore = true
+		}
+	}
+	name := b.String()
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Link represents an OpenAPI response link to a follow-up operation.
+type Link struct {
+	Name         string
+	Status       string            // Response status the link is declared on (e.g. 201)
+	OperationID  string            // Target operationId
+	OperationRef string            // Target JSON pointer (e.g. #/paths/~1orders~1{id}/get)
+	Parameters   map[string]string // Target parameter name -> runtime expression
+	Description  string
+}
+
+// Tag represents a top-level OpenAPI tag.
+type Tag struct {
+	Name        string
+	Description string
 }
 
 // Parameter represents an OpenAPI parameter (path, query, header).
@@ -16,6 +76,7 @@ type Parameter struct {
 	In       string // path, query, header
 	Required bool
 	Schema   map[string]interface{}
+	Pointer  string // JSON pointer into the operation (e.g. /parameters/2)
 }
 
 // RequestBody represents OpenAPI requestBody (e.g. application/json schema).
@@ -30,6 +91,7 @@ type MCPToolParam struct {
 	In       string // path, query, header
 	Required bool
 	Schema   map[string]interface{}
+	Pointer  string // JSON pointer into the operation, if mapped from a spec
 }
 
 // MCPToolBody represents the request body schema for an MCP tool.
@@ -48,4 +110,176 @@ type MCPTool struct {
 	Method      string                 // HTTP method (GET, POST, etc.)
 	Path        string                 // API path (e.g. /ping)
 	BaseURL     string                 // Base URL from OpenAPI servers (e.g. http://localhost:8080)
+	Source      string                 // Source name when several specs are merged; empty for a single spec
+	Annotations *MCPToolAnnotations    // Behavior hints derived from the HTTP method
+	HTTP        MCPToolHTTP            // Request settings overriding the runtime defaults
+	Errors      map[string]string      // Documented error responses (status -> description), reported with failures
+	Response    map[string]interface{} // JSON Schema of the successful response; nil if it is not JSON
+	Fields      []string               // Response fields the fields argument selects from; nil when projection is off
+	Confirm     bool                   // Ask the user to confirm each request before it is sent
+	Elicit      []string               // Required parameters the user is asked for when a call leaves them out
+	Pagination  *MCPPagination         // How to fetch further pages of a list response; nil if not paginated
+	Async       *MCPAsync              // How to wait for an operation answering 202 Accepted; nil if it is not async
+	ContentType string                 // Content type of the successful response; "" if it has none
+	Stream      string                 // "sse" or "ndjson" when the response is a stream of events read as they arrive
+	Warnings    []Warning              // Degradations introduced while mapping the operation
+}
+
+// MCPToolHTTP overrides the timeout, retry, rate limit and response size
+// defaults of the generated server for one operation (from its extensions). Zero values keep
+// the defaults, which the generated server reads from environment variables.
+type MCPToolHTTP struct {
+	TimeoutMs   int           // x-mcp-timeout: milliseconds before a request attempt is aborted
+	Retries     *int          // x-mcp-retries: maximum number of retries; 0 disables retrying
+	Idempotent  bool          // x-mcp-idempotent: retry even though the method is not idempotent
+	RateLimit   *MCPRateLimit // x-ratelimit: limits of the operation, on top of the global ones
+	MaxResponse int           // x-mcp-max-response: characters of a response passed on before it is truncated
+}
+
+// IsZero reports whether h keeps every default.
+func (h MCPToolHTTP) IsZero() bool {
+	return h.TimeoutMs == 0 && h.Retries == nil && !h.Idempotent && h.RateLimit == nil && h.MaxResponse == 0
+}
+
+// MCPPagination describes how a list operation pages through its results,
+// so the generated tool can fetch several pages and merge their items.
+type MCPPagination struct {
+	Style      string // "page", "offset", "cursor" or "link" (the Link header of RFC 8288)
+	Param      string // Query parameter carrying the page number, offset or cursor; empty for link
+	SizeParam  string // Query parameter carrying the page size; may be empty
+	Start      int    // Number of the first page, for the page style
+	Items      string // Dotted path of the item array in the response; empty when the response is the array
+	NextCursor string // Dotted path of the next cursor in the response, for the cursor style
+}
+
+// MCPAsync describes how the generated tool waits for an operation that
+// answers 202 Accepted: it polls the status URL the response points to until
+// the operation finishes. Zero fields keep the runtime defaults.
+type MCPAsync struct {
+	StatusURL  string // Dotted path of the status URL in the 202 body, when no Location header gives it
+	IntervalMs int    // Milliseconds between polls, unless the server sends Retry-After
+	TimeoutMs  int    // Milliseconds to wait for completion before returning the status so far
+}
+
+// MCPRateLimit caps the request rate (a token bucket) and the number of
+// requests in flight, for the whole server or one operation. Zero fields are
+// unlimited.
+type MCPRateLimit struct {
+	Limit       int // Requests per window
+	WindowMs    int // Window of Limit in milliseconds
+	Burst       int // Requests allowed at once before the rate applies; 0 means Limit
+	Concurrency int // Maximum requests in flight
+}
+
+// MCPToolAnnotations are the MCP tool behavior hints reported to clients.
+type MCPToolAnnotations struct {
+	ReadOnlyHint    bool // Tool does not modify its environment
+	DestructiveHint bool // Tool may overwrite or delete data
+	IdempotentHint  bool // Repeating the call with the same arguments has no additional effect
+	OpenWorldHint   bool // Tool interacts with an external system
+}
+
+// MCPResource represents an MCP resource (or resource template) derived from a
+// read-only OpenAPI GET operation.
+type MCPResource struct {
+	Name        string
+	Description string
+	URI         string            // Resource URI or URI template (e.g. api://products/{productId})
+	MimeType    string            // MIME type reported to clients (e.g. application/json)
+	Params      []MCPToolParam    // Path parameters; non-empty only for resource templates
+	Path        string            // API path (e.g. /products/{productId})
+	BaseURL     string            // Base URL from OpenAPI servers
+	Source      string            // Source name when several specs are merged; empty for a single spec
+	HTTP        MCPToolHTTP       // Request settings overriding the runtime defaults
+	Errors      map[string]string // Documented error responses (status -> description), reported with failures
+	Warnings    []Warning         // Degradations introduced while mapping the operation
+}
+
+// IsTemplate reports whether the resource is a URI template with path parameters.
+func (r *MCPResource) IsTemplate() bool {
+	return len(r.Params) > 0
+}
+
+// MCPPrompt represents an MCP prompt guiding agents through multi-step API usage.
+type MCPPrompt struct {
+	Name        string
+	Description string
+	Text        string // Prompt message returned when the prompt is loaded
+}
+
+// MCPWorkflow represents a composite MCP tool that runs a sequence of API calls
+// (from an Arazzo workflow), passing outputs between steps.
+type MCPWorkflow struct {
+	Name        string
+	Description string
+	InputSchema map[string]interface{} // JSON Schema for workflow inputs
+	Steps       []MCPWorkflowStep
+	Outputs     map[string]string // Output name -> runtime expression
+}
+
+// MCPWorkflowStep is one API call within an MCPWorkflow.
+type MCPWorkflowStep struct {
+	ID              string
+	Tool            *MCPTool // Resolved operation (method, path, base URL)
+	Params          []MCPWorkflowParam
+	Body            interface{}       // Request body payload; may contain runtime expressions
+	SuccessCriteria []string          // Simple conditions (e.g. $statusCode == 200); empty means 2xx
+	Outputs         map[string]string // Output name -> runtime expression
+}
+
+// MCPWorkflowParam is a parameter value for a workflow step; Value is a literal
+// or a runtime expression.
+type MCPWorkflowParam struct {
+	Name  string
+	In    string // path, query, header
+	Value interface{}
+}
+
+// Info is the OpenAPI info object of a spec.
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// MCPServer groups everything generated into a single MCP server.
+type MCPServer struct {
+	Name         string        // Server and npm package name
+	Version      string        // Semantic version of the server and package
+	Instructions string        // Guidance sent to clients when they connect; may be empty
+	RateLimit    *MCPRateLimit // Global limits from the document's x-ratelimit; nil keeps the runtime defaults
+	Tools        []*MCPTool
+	Resources    []*MCPResource
+	Prompts      []*MCPPrompt
+	Workflows    []*MCPWorkflow
+}
+
+// Warning records something an operation declares that the generated server
+// cannot do (or does in a degraded way).
+type Warning struct {
+	Stage     string `json:"stage"`            // parse, mapping or generate
+	Source    string `json:"source,omitempty"` // Source name when several specs are merged
+	Operation string `json:"operation"`        // e.g. POST /files
+	Pointer   string `json:"pointer"`          // JSON pointer into the source spec
+	Message   string `json:"message"`
+}
+
+// Report summarizes a generation run.
+type Report struct {
+	Tools     int       `json:"tools"`
+	Resources int       `json:"resources"`
+	Prompts   int       `json:"prompts"`
+	Workflows int       `json:"workflows"`
+	Warnings  []Warning `json:"warnings"`
+}
+
+// OperationPointer returns the JSON pointer of an operation, e.g.
+// /paths/~1products~1{id}/get.
+func OperationPointer(path, method string) string {
+	return "/paths/" + PointerEscape(path) + "/" + strings.ToLower(method)
+}
+
+// PointerEscape escapes one JSON pointer reference token.
+func PointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
 }
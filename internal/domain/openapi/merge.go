@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"fmt"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Named is one parsed spec of a server built from several, with the name and
+// optional tool name prefix of its source.
+type Named struct {
+	Name   string
+	Prefix string
+	Result *ParseResult
+}
+
+// Merge combines several parsed specs into one result. The operations of each
+// get a model.Source with its name, prefix and base URL, so tools keep a
+// per-source base URL, and its warnings are attributed to it; the merged
+// result has no BaseURL of its own. Info and extensions are kept only when
+// there is one spec. Names must be unique, also as environment variable
+// names (see model.EnvName).
+func Merge(specs []Named) (*ParseResult, error) {
+	merged := &ParseResult{}
+	envNames := make(map[string]string)
+	for _, n := range specs {
+		if model.EnvName(n.Name) == "" {
+			return nil, fmt.Errorf("every merged spec needs a name with a letter or digit, got %q", n.Name)
+		}
+		if other, ok := envNames[model.EnvName(n.Name)]; ok {
+			if other == n.Name {
+				return nil, fmt.Errorf("specs share the name %q", n.Name)
+			}
+			return nil, fmt.Errorf("specs %q and %q would share the environment variable %s_BASE_URL; give them distinct names", other, n.Name, model.EnvName(n.Name))
+		}
+		envNames[model.EnvName(n.Name)] = n.Name
+
+		src := &model.Source{Name: n.Name, Prefix: n.Prefix, BaseURL: n.Result.BaseURL}
+		for _, op := range n.Result.Operations {
+			op.Source = src
+		}
+		for i := range n.Result.Warnings {
+			n.Result.Warnings[i].Source = n.Name
+		}
+		merged.Operations = append(merged.Operations, n.Result.Operations...)
+		merged.Tags = append(merged.Tags, n.Result.Tags...)
+		merged.Warnings = append(merged.Warnings, n.Result.Warnings...)
+		if len(specs) == 1 {
+			merged.Info = n.Result.Info
+			merged.Extensions = n.Result.Extensions
+		}
+	}
+	return merged, nil
+}
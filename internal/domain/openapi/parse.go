@@ -1,8 +1,13 @@
 package openapi
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -25,18 +30,72 @@ func (e *UnsupportedVersionError) Error() string {
 // ParseResult holds the result of parsing an OpenAPI spec.
 type ParseResult struct {
 	Operations []*model.Operation
-	BaseURL    string // First server URL, if present
+	BaseURL    string                 // First server URL, if present
+	Info       model.Info             // Title, version and description of the API
+	Tags       []model.Tag            // Top-level tags with descriptions
+	Extensions map[string]interface{} // Specification extensions (x-*) of the document
+	Warnings   []model.Warning
 }
 
 // Parse reads an OpenAPI 3.x document from r (YAML or JSON) and returns
 // a list of operations and the base URL. Rejects OpenAPI 2.0 with ErrOpenAPI2Unsupported.
 func Parse(r io.Reader) (*ParseResult, error) {
+	return ParseAt(r, "")
+}
+
+// ParseAt is Parse for a document read from the file at path: $refs to other
+// local files are resolved relative to it (remote refs are not fetched). An empty path behaves like Parse.
+func ParseAt(r io.Reader, path string) (*ParseResult, error) {
+	doc, err := LoadAt(r, path)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := ""
+	if doc.Servers != nil && len(doc.Servers) > 0 {
+		baseURL = strings.TrimRight(doc.Servers[0].URL, "/")
+	}
+	var tags []model.Tag
+	for _, t := range doc.Tags {
+		if t != nil {
+			tags = append(tags, model.Tag{Name: t.Name, Description: t.Description})
+		}
+	}
+	var info model.Info
+	if doc.Info != nil {
+		info = model.Info{Title: doc.Info.Title, Version: doc.Info.Version, Description: doc.Info.Description}
+	}
+	ops, warnings := extractOperations(doc)
+	return &ParseResult{
+		Operations: ops,
+		BaseURL:    baseURL,
+		Info:       info,
+		Tags:       tags,
+		Extensions: extensions(doc.Extensions),
+		Warnings:   warnings,
+	}, nil
+}
+
+// Load reads an OpenAPI 3.x document from r (YAML or JSON) without
+// extracting operations. Rejects OpenAPI 2.0 with ErrOpenAPI2Unsupported.
+func Load(r io.Reader) (*openapi3.T, error) {
+	return LoadAt(r, "")
+}
+
+// LoadAt is Load for a document read from the file at path (see ParseAt).
+func LoadAt(r io.Reader, path string) (*openapi3.T, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 	loader := openapi3.NewLoader()
-	doc, err := loader.LoadFromData(data)
+	var doc *openapi3.T
+	if path != "" {
+		loader.IsExternalRefsAllowed = true
+		loader.ReadFromURIFunc = openapi3.ReadFromFile // Never fetch remote refs
+		doc, err = loader.LoadFromDataWithPath(data, &url.URL{Path: filepath.ToSlash(path)})
+	} else {
+		doc, err = loader.LoadFromData(data)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -47,33 +106,69 @@ func Parse(r io.Reader) (*ParseResult, error) {
 	if len(doc.OpenAPI) >= 1 && doc.OpenAPI[0] == '2' {
 		return nil, ErrOpenAPI2Unsupported
 	}
-	baseURL := ""
-	if doc.Servers != nil && len(doc.Servers) > 0 {
-		baseURL = strings.TrimRight(doc.Servers[0].URL, "/")
-	}
-	return &ParseResult{
-		Operations: extractOperations(doc),
-		BaseURL:    baseURL,
-	}, nil
+	return doc, nil
+}
+
+// Validate checks doc against the OpenAPI 3.x specification.
+func Validate(doc *openapi3.T) error {
+	return doc.Validate(context.Background())
 }
 
-func extractOperations(doc *openapi3.T) []*model.Operation {
+// methodOrder is the order of operations within an OpenAPI path item.
+var methodOrder = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// extractOperations maps every operation in doc, sorted by path and then in
+// path item order so generated output and inspect are stable, and records a
+// warning for each part of an operation that is dropped (request bodies
+// without a JSON schema) and once per path item for its ignored path-level
+// parameters.
+func extractOperations(doc *openapi3.T) ([]*model.Operation, []model.Warning) {
 	var out []*model.Operation
-	for path, pathItem := range doc.Paths.Map() {
+	var warnings []model.Warning
+	for _, path := range sortedPaths(doc) {
+		pathItem := doc.Paths.Value(path)
 		if pathItem == nil {
 			continue
 		}
-		for method, op := range pathItem.Operations() {
+		for i, p := range pathItem.Parameters {
+			if p != nil && p.Value != nil {
+				warnings = append(warnings, model.Warning{
+					Stage:     "parse",
+					Operation: path,
+					Pointer:   fmt.Sprintf("/paths/%s/parameters/%d", model.PointerEscape(path), i),
+					Message:   fmt.Sprintf("path-level %s parameter %q is ignored; declare it on the operation", p.Value.In, p.Value.Name),
+				})
+			}
+		}
+		for _, method := range methodOrder {
+			op := pathItem.GetOperation(method)
 			if op == nil {
 				continue
 			}
+			warn := func(pointer, format string, args ...interface{}) {
+				warnings = append(warnings, model.Warning{
+					Stage:     "parse",
+					Operation: method + " " + path,
+					Pointer:   pointer,
+					Message:   fmt.Sprintf(format, args...),
+				})
+			}
+			ptr := model.OperationPointer(path, method)
 			m := &model.Operation{
 				Path:        path,
 				Method:      method,
 				OperationID: op.OperationID,
 				Summary:     op.Summary,
+				Tags:        op.Tags,
+				Links:       extractLinks(op),
+				Errors:      extractErrors(op),
+				Response:    extractResponse(op),
+				ContentType: extractContentType(op),
+				Headers:     extractHeaders(op),
+				Accepted:    op.Responses != nil && op.Responses.Value("202") != nil,
+				Extensions:  extensions(op.Extensions),
 			}
-			for _, p := range op.Parameters {
+			for i, p := range op.Parameters {
 				if p == nil || p.Value == nil {
 					continue
 				}
@@ -86,11 +181,18 @@ func extractOperations(doc *openapi3.T) []*model.Operation {
 					In:       p.Value.In,
 					Required: p.Value.Required,
 					Schema:   schema,
+					Pointer:  fmt.Sprintf("/parameters/%d", i),
 				})
 			}
 			if op.RequestBody != nil && op.RequestBody.Value != nil {
 				ct := op.RequestBody.Value.Content.Get("application/json")
-				if ct != nil && ct.Schema != nil && ct.Schema.Value != nil {
+				switch {
+				case ct == nil:
+					warn(ptr+"/requestBody", "request body has no application/json content (found %s); the tool sends no body",
+						strings.Join(contentTypes(op.RequestBody.Value.Content), ", "))
+				case ct.Schema == nil || ct.Schema.Value == nil:
+					warn(ptr+"/requestBody/content/application~1json", "JSON request body has no schema; the tool sends no body")
+				default:
 					m.RequestBody = &model.RequestBody{
 						Required: op.RequestBody.Value.Required,
 						Schema:   schemaToMap(ct.Schema.Value),
@@ -100,6 +202,191 @@ func extractOperations(doc *openapi3.T) []*model.Operation {
 			out = append(out, m)
 		}
 	}
+	return out, warnings
+}
+
+// extensions returns a copy of the x-* extensions in ext, or nil if there are none.
+func extensions(ext map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	for k, v := range ext {
+		if !strings.HasPrefix(k, "x-") {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{})
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func sortedPaths(doc *openapi3.T) []string {
+	paths := make([]string, 0, doc.Paths.Len())
+	for path := range doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func contentTypes(content openapi3.Content) []string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// extractErrors returns the description of every response of op that is not
+// a 2xx one, by status, or nil if there are none.
+func extractErrors(op *openapi3.Operation) map[string]string {
+	if op.Responses == nil {
+		return nil
+	}
+	var out map[string]string
+	for status, resp := range op.Responses.Map() {
+		if strings.HasPrefix(status, "2") || resp == nil || resp.Value == nil || resp.Value.Description == nil {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		if status != "default" {
+			status = strings.ToUpper(status) // 4xx -> 4XX
+		}
+		out[status] = *resp.Value.Description
+	}
+	return out
+}
+
+// extractHeaders returns the names of the headers declared on the 2xx
+// responses of op, sorted, or nil.
+func extractHeaders(op *openapi3.Operation) []string {
+	seen := make(map[string]bool)
+	for _, resp := range successResponses(op) {
+		for name := range resp.Headers {
+			seen[name] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// successResponses returns the 2xx responses of op in order: 200 before 201 before 2XX.
+func successResponses(op *openapi3.Operation) []*openapi3.Response {
+	if op.Responses == nil {
+		return nil
+	}
+	responses := op.Responses.Map()
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	var out []*openapi3.Response
+	for _, status := range statuses {
+		if resp := responses[status]; resp != nil && resp.Value != nil {
+			out = append(out, resp.Value)
+		}
+	}
+	return out
+}
+
+// extractContentType returns the content type of the first 2xx response of op
+// with content: a JSON one if there is one, else the first in order. It
+// returns "" if no 2xx response has content.
+func extractContentType(op *openapi3.Operation) string {
+	for _, resp := range successResponses(op) {
+		if len(resp.Content) == 0 {
+			continue
+		}
+		types := contentTypes(resp.Content)
+		for _, ct := range types {
+			if isJSON(ct) {
+				return ct
+			}
+		}
+		return types[0]
+	}
+	return ""
+}
+
+// extractResponse returns the schema of the first 2xx response of op with
+// JSON content, or nil.
+func extractResponse(op *openapi3.Operation) map[string]interface{} {
+	for _, resp := range successResponses(op) {
+		for _, ct := range contentTypes(resp.Content) {
+			media := resp.Content[ct]
+			if isJSON(ct) && media != nil && media.Schema != nil && media.Schema.Value != nil {
+				return schemaToMap(media.Schema.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// isJSON reports whether contentType is application/json or a +json type.
+func isJSON(contentType string) bool {
+	ct := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// extractLinks collects the links declared on op's responses, ordered by
+// response status and link name.
+func extractLinks(op *openapi3.Operation) []model.Link {
+	if op.Responses == nil {
+		return nil
+	}
+	responses := op.Responses.Map()
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var out []model.Link
+	for _, status := range statuses {
+		resp := responses[status]
+		if resp == nil || resp.Value == nil {
+			continue
+		}
+		names := make([]string, 0, len(resp.Value.Links))
+		for name := range resp.Value.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			l := resp.Value.Links[name]
+			if l == nil || l.Value == nil {
+				continue
+			}
+			var params map[string]string
+			if len(l.Value.Parameters) > 0 {
+				params = make(map[string]string, len(l.Value.Parameters))
+				for k, v := range l.Value.Parameters {
+					params[k] = fmt.Sprint(v)
+				}
+			}
+			out = append(out, model.Link{
+				Name:         name,
+				Status:       status,
+				OperationID:  l.Value.OperationID,
+				OperationRef: l.Value.OperationRef,
+				Parameters:   params,
+				Description:  l.Value.Description,
+			})
+		}
+	}
 	return out
 }
 
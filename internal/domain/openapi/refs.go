@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalRefs returns the local files the document at path references through
+// $ref, directly or through other referenced files, sorted and without path
+// itself. Remote refs are skipped; files that cannot be read or parsed are
+// listed but not followed.
+func LocalRefs(path string) []string {
+	seen := map[string]bool{filepath.Clean(path): true}
+	var out []string
+	queue := []string{filepath.Clean(path)}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var doc interface{}
+		if yaml.Unmarshal(data, &doc) != nil {
+			continue
+		}
+		collectRefs(doc, func(ref string) {
+			if i := strings.IndexByte(ref, '#'); i >= 0 {
+				ref = ref[:i]
+			}
+			if ref == "" || strings.Contains(ref, "://") {
+				return
+			}
+			target := filepath.Join(filepath.Dir(file), filepath.FromSlash(ref))
+			if !seen[target] {
+				seen[target] = true
+				out = append(out, target)
+				queue = append(queue, target)
+			}
+		})
+	}
+	sort.Strings(out)
+	return out
+}
+
+// collectRefs calls found with the value of every $ref in v.
+func collectRefs(v interface{}, found func(ref string)) {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		for k, child := range n {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				found(ref)
+				continue
+			}
+			collectRefs(child, found)
+		}
+	case []interface{}:
+		for _, child := range n {
+			collectRefs(child, found)
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package overlay
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// node is a value selected by a JSONPath query, together with accessors to
+// replace or remove it in its parent container.
+type node struct {
+	value interface{}
+	set   func(interface{})
+	del   func()
+}
+
+// selectorKind enumerates the supported bracket/dot selectors.
+type selectorKind int
+
+const (
+	selName selectorKind = iota
+	selWildcard
+	selIndex
+	selFilter
+)
+
+type selector struct {
+	kind   selectorKind
+	name   string
+	index  int
+	filter string
+}
+
+type segment struct {
+	recursive bool
+	selectors []selector
+}
+
+// query evaluates a JSONPath expression against root. Supported syntax:
+// $, .name, ['name'], ["a","b"], .*, [*], [n], ..name, ..*, and filters
+// [?(@.field == 'value')] with ==, !=, <, <=, >, >=, &&, ||, ! (on an
+// existence test or a parenthesized filter) and existence tests.
+func query(root *node, path string) ([]*node, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*node{root}
+	for _, seg := range segs {
+		var next []*node
+		for _, n := range nodes {
+			candidates := []*node{n}
+			if seg.recursive {
+				candidates = descendants(n)
+			}
+			for _, c := range candidates {
+				for _, sel := range seg.selectors {
+					matched, err := applySelector(c, sel)
+					if err != nil {
+						return nil, err
+					}
+					next = append(next, matched...)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+func parsePath(path string) ([]segment, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+	var segs []segment
+	i := 1
+	for i < len(path) {
+		var seg segment
+		switch {
+		case strings.HasPrefix(path[i:], ".."):
+			seg.recursive = true
+			i += 2
+			if i < len(path) && path[i] == '[' {
+				sels, n, err := parseBracket(path[i:])
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = sels
+				i += n
+			} else {
+				name, n := parseName(path[i:])
+				if n == 0 {
+					return nil, fmt.Errorf("JSONPath %q: expected name after ..", path)
+				}
+				seg.selectors = []selector{nameOrWildcard(name)}
+				i += n
+			}
+		case path[i] == '.':
+			i++
+			name, n := parseName(path[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("JSONPath %q: expected name after .", path)
+			}
+			seg.selectors = []selector{nameOrWildcard(name)}
+			i += n
+		case path[i] == '[':
+			sels, n, err := parseBracket(path[i:])
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = sels
+			i += n
+		default:
+			return nil, fmt.Errorf("JSONPath %q: unexpected %q at offset %d", path, path[i], i)
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+func nameOrWildcard(name string) selector {
+	if name == "*" {
+		return selector{kind: selWildcard}
+	}
+	return selector{kind: selName, name: name}
+}
+
+// parseName reads a dot-notation member name (or *) and returns it with the
+// number of bytes consumed.
+func parseName(s string) (string, int) {
+	if strings.HasPrefix(s, "*") {
+		return "*", 1
+	}
+	n := 0
+	for n < len(s) && s[n] != '.' && s[n] != '[' {
+		n++
+	}
+	return s[:n], n
+}
+
+// parseBracket parses a [...] selector list starting at s[0] == '[' and
+// returns the selectors and the number of bytes consumed.
+func parseBracket(s string) ([]selector, int, error) {
+	end := closingBracket(s)
+	if end < 0 {
+		return nil, 0, fmt.Errorf("JSONPath: unterminated [ in %q", s)
+	}
+	inner := strings.TrimSpace(s[1:end])
+	if strings.HasPrefix(inner, "?") {
+		return []selector{{kind: selFilter, filter: strings.TrimSpace(inner[1:])}}, end + 1, nil
+	}
+	var sels []selector
+	for _, part := range splitTopLevel(inner, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "*":
+			sels = append(sels, selector{kind: selWildcard})
+		case isQuoted(part):
+			sels = append(sels, selector{kind: selName, name: unquote(part)})
+		default:
+			idx, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, 0, fmt.Errorf("JSONPath: invalid selector %q", part)
+			}
+			sels = append(sels, selector{kind: selIndex, index: idx})
+		}
+	}
+	return sels, end + 1, nil
+}
+
+// closingBracket returns the index of the ] matching s[0], skipping quoted
+// strings and nested brackets, or -1.
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on sep outside quotes, brackets and parentheses.
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+func unquote(s string) string {
+	inner := s[1 : len(s)-1]
+	return strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(inner)
+}
+
+// children returns the direct children of n; object members are ordered by key.
+func children(n *node) []*node {
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]*node, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, memberNode(v, k))
+		}
+		return out
+	case []interface{}:
+		out := make([]*node, 0, len(v))
+		for i := range v {
+			out = append(out, elementNode(v, i))
+		}
+		return out
+	}
+	return nil
+}
+
+func memberNode(m map[string]interface{}, key string) *node {
+	return &node{
+		value: m[key],
+		set:   func(x interface{}) { m[key] = x },
+		del:   func() { delete(m, key) },
+	}
+}
+
+// elementNode marks removed elements with the removed sentinel; callers
+// compact arrays once all removals of an action are done.
+func elementNode(arr []interface{}, i int) *node {
+	return &node{
+		value: arr[i],
+		set:   func(x interface{}) { arr[i] = x },
+		del:   func() { arr[i] = removed{} },
+	}
+}
+
+// removed marks an array element deleted by a remove action.
+type removed struct{}
+
+// descendants returns n followed by all nodes below it, depth first.
+func descendants(n *node) []*node {
+	out := []*node{n}
+	for _, c := range children(n) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+func applySelector(n *node, sel selector) ([]*node, error) {
+	switch sel.kind {
+	case selName:
+		if m, ok := n.value.(map[string]interface{}); ok {
+			if _, exists := m[sel.name]; exists {
+				return []*node{memberNode(m, sel.name)}, nil
+			}
+		}
+		return nil, nil
+	case selWildcard:
+		return children(n), nil
+	case selIndex:
+		arr, ok := n.value.([]interface{})
+		if !ok {
+			return nil, nil
+		}
+		idx := sel.index
+		if idx < 0 {
+			idx += len(arr)
+		}
+		if idx < 0 || idx >= len(arr) {
+			return nil, nil
+		}
+		return []*node{elementNode(arr, idx)}, nil
+	case selFilter:
+		var out []*node
+		for _, c := range children(n) {
+			ok, err := evalFilter(sel.filter, c.value)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, c)
+			}
+		}
+		return out, nil
+	}
+	return nil, nil
+}
+
+// evalFilter evaluates a filter expression with @ bound to value.
+func evalFilter(expr string, value interface{}) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "(") && closingParen(expr) == len(expr)-1 {
+		expr = expr[1 : len(expr)-1]
+	}
+	if ors := splitTopLevel(expr, "||"); len(ors) > 1 {
+		for _, e := range ors {
+			ok, err := evalFilter(e, value)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	if ands := splitTopLevel(expr, "&&"); len(ands) > 1 {
+		for _, e := range ands {
+			ok, err := evalFilter(e, value)
+			if err != nil || !ok {
+				return ok, err
+			}
+		}
+		return true, nil
+	}
+	if strings.HasPrefix(expr, "!") {
+		ok, err := evalFilter(expr[1:], value)
+		return !ok && err == nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		parts := splitTopLevel(expr, op)
+		if len(parts) != 2 {
+			continue
+		}
+		left, err := operand(strings.TrimSpace(parts[0]), value)
+		if err != nil {
+			return false, err
+		}
+		right, err := operand(strings.TrimSpace(parts[1]), value)
+		if err != nil {
+			return false, err
+		}
+		return compare(left, right, op), nil
+	}
+	if !strings.HasPrefix(expr, "@") {
+		return false, fmt.Errorf("JSONPath: unsupported filter %q; filters support @ paths, literals, ==, !=, <, <=, >, >=, &&, ||, ! and parentheses", expr)
+	}
+	nodes, err := query(&node{value: value}, "$"+expr[1:])
+	if err != nil {
+		return false, err
+	}
+	return len(nodes) > 0, nil
+}
+
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// operand resolves a filter operand: a relative path (@...), a quoted
+// string, a number, true, false or null. Missing paths resolve to nothing.
+func operand(s string, value interface{}) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, "@"):
+		nodes, err := query(&node{value: value}, "$"+s[1:])
+		if err != nil || len(nodes) == 0 {
+			return nothing{}, err
+		}
+		return nodes[0].value, nil
+	case isQuoted(s):
+		return unquote(s), nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("JSONPath: invalid literal %q", s)
+	}
+	return f, nil
+}
+
+// nothing is the result of a relative path that selects no node.
+type nothing struct{}
+
+func compare(a, b interface{}, op string) bool {
+	if _, ok := a.(nothing); ok {
+		return op == "!="
+	}
+	if _, ok := b.(nothing); ok {
+		return op == "!="
+	}
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch op {
+			case "==":
+				return fa == fb
+			case "!=":
+				return fa != fb
+			case "<":
+				return fa < fb
+			case "<=":
+				return fa <= fb
+			case ">":
+				return fa > fb
+			case ">=":
+				return fa >= fb
+			}
+		}
+	}
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			switch op {
+			case "<":
+				return sa < sb
+			case "<=":
+				return sa <= sb
+			case ">":
+				return sa > sb
+			case ">=":
+				return sa >= sb
+			}
+		}
+	}
+	eq := fmt.Sprintf("%T:%v", a, a) == fmt.Sprintf("%T:%v", b, b)
+	switch op {
+	case "==":
+		return eq
+	case "!=":
+		return !eq
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,186 @@
+package overlay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay is an OpenAPI Overlay 1.0 document: an ordered list of actions
+// applied to a target OpenAPI document.
+type Overlay struct {
+	Overlay string   `yaml:"overlay"`
+	Extends string   `yaml:"extends"` // Optional URL or path of the document the overlay targets
+	Actions []Action `yaml:"actions"`
+}
+
+// Action updates or removes every node matched by its JSONPath target.
+type Action struct {
+	Target      string      `yaml:"target"`
+	Description string      `yaml:"description"`
+	Update      interface{} `yaml:"update"`
+	Remove      bool        `yaml:"remove"`
+}
+
+// Parse reads an Overlay 1.x document from r (YAML or JSON).
+func Parse(r io.Reader) (*Overlay, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var ov Overlay
+	if err := yaml.Unmarshal(data, &ov); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(ov.Overlay, "1.") {
+		return nil, fmt.Errorf("unsupported Overlay version %q; use Overlay 1.x", ov.Overlay)
+	}
+	if len(ov.Actions) == 0 {
+		return nil, fmt.Errorf("no actions found in overlay")
+	}
+	for i, a := range ov.Actions {
+		if a.Target == "" {
+			return nil, fmt.Errorf("actions[%d]: missing target", i)
+		}
+		if !a.Remove && a.Update == nil {
+			return nil, fmt.Errorf("actions[%d]: needs update or remove", i)
+		}
+		ov.Actions[i].Update = normalize(a.Update)
+	}
+	return &ov, nil
+}
+
+// Apply applies the overlay actions in order to doc (YAML or JSON) and
+// returns the resulting document as JSON. Update merges objects recursively
+// and appends to arrays; remove deletes the matched nodes. Every action whose
+// target matches nothing is reported in the returned error.
+func Apply(doc []byte, ov *Overlay) ([]byte, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(doc, &raw); err != nil {
+		return nil, err
+	}
+	raw = normalize(raw)
+	root := &node{value: raw}
+	root.set = func(v interface{}) { root.value = v }
+
+	var errs []error
+	for i, a := range ov.Actions {
+		matches, err := query(root, a.Target)
+		if err != nil {
+			return nil, fmt.Errorf("actions[%d]: %w", i, err)
+		}
+		if len(matches) == 0 {
+			errs = append(errs, fmt.Errorf("actions[%d]: target %s matched nothing", i, a.Target))
+			continue
+		}
+		for _, m := range matches {
+			switch {
+			case a.Remove && m.del != nil:
+				m.del()
+			case a.Remove:
+				return nil, fmt.Errorf("actions[%d]: cannot remove the document root", i)
+			default:
+				m.set(merge(m.value, clone(a.Update)))
+			}
+		}
+		root.value = compact(root.value)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return json.Marshal(root.value)
+}
+
+// merge returns target updated with update: objects merge recursively,
+// arrays get update appended (element-wise if update is an array), and any
+// other target is replaced.
+func merge(target, update interface{}) interface{} {
+	switch t := target.(type) {
+	case map[string]interface{}:
+		u, ok := update.(map[string]interface{})
+		if !ok {
+			return update
+		}
+		for k, v := range u {
+			if cur, exists := t[k]; exists {
+				t[k] = merge(cur, v)
+			} else {
+				t[k] = v
+			}
+		}
+		return t
+	case []interface{}:
+		if u, ok := update.([]interface{}); ok {
+			return append(t, u...)
+		}
+		return append(t, update)
+	}
+	return update
+}
+
+// clone deep-copies a decoded YAML/JSON value so each match gets its own copy.
+func clone(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, c := range t {
+			out[k] = clone(c)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, c := range t {
+			out[i] = clone(c)
+		}
+		return out
+	}
+	return v
+}
+
+// compact drops array elements marked as removed, recursively.
+func compact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, c := range t {
+			t[k] = compact(c)
+		}
+		return t
+	case []interface{}:
+		out := t[:0]
+		for _, c := range t {
+			if _, gone := c.(removed); !gone {
+				out = append(out, compact(c))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// normalize converts YAML-decoded maps with non-string keys (e.g. unquoted
+// response codes) into map[string]interface{} so the document encodes as JSON.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, c := range t {
+			t[k] = normalize(c)
+		}
+		return t
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, c := range t {
+			out[fmt.Sprint(k)] = normalize(c)
+		}
+		return out
+	case []interface{}:
+		for i, c := range t {
+			t[i] = normalize(c)
+		}
+		return t
+	}
+	return v
+}
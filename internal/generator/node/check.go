@@ -0,0 +1,104 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+
+	"bakemcp/internal/domain/model"
+)
+
+// Check reports what the generated entry script cannot express for the tools
+// in srv: header and cookie parameters (accepted but never sent), request
+// bodies without object properties, and schemas that fall back to z.any().
+func Check(srv *model.MCPServer) []model.Warning {
+	var out []model.Warning
+	for _, t := range srv.Tools {
+		opPtr := model.OperationPointer(t.Path, t.Method)
+		warn := func(pointer, format string, args ...interface{}) {
+			out = append(out, model.Warning{
+				Stage:     "generate",
+				Source:    t.Source,
+				Operation: t.Method + " " + t.Path,
+				Pointer:   opPtr + pointer,
+				Message:   fmt.Sprintf(format, args...),
+			})
+		}
+		for i, p := range t.Params {
+			ptr := p.Pointer
+			if ptr == "" {
+				ptr = fmt.Sprintf("/parameters/%d", i)
+			}
+			if p.In != "path" && p.In != "query" {
+				warn(ptr, "%s parameter %q is accepted but not sent", p.In, p.Name)
+			}
+			anySchemas(p.Schema, ptr+"/schema", func(at, reason string) {
+				warn(at, "parameter %q: %s; accepted as any value", p.Name, reason)
+			})
+		}
+		if t.Body == nil {
+			continue
+		}
+		bodyPtr := "/requestBody/content/application~1json/schema"
+		props, _ := t.Body.Schema["properties"].(map[string]interface{})
+		if len(props) == 0 {
+			warn(bodyPtr, "request body schema has no object properties; the tool sends an empty object")
+			continue
+		}
+		for _, name := range sortedKeys(props) {
+			prop, _ := props[name].(map[string]interface{})
+			anySchemas(prop, bodyPtr+"/properties/"+model.PointerEscape(name), func(at, reason string) {
+				warn(at, "body property %q: %s; accepted as any value", name, reason)
+			})
+		}
+	}
+	return out
+}
+
+// anySchemas calls found for every location in schema that schemaToZod
+// renders as z.any(), with the pointer of that location and the reason.
+func anySchemas(schema map[string]interface{}, pointer string, found func(pointer, reason string)) {
+	if schema == nil {
+		found(pointer, "no schema")
+		return
+	}
+	if arr, ok := schema["enum"].([]interface{}); ok && len(arr) > 0 {
+		return
+	}
+	switch typ, _ := schema["type"].(string); typ {
+	case "string", "number", "integer", "boolean":
+	case "array":
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			found(pointer, "array without items")
+			return
+		}
+		anySchemas(items, pointer+"/items", found)
+	case "object":
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(props) {
+			prop, _ := props[name].(map[string]interface{})
+			anySchemas(prop, pointer+"/properties/"+model.PointerEscape(name), found)
+		}
+	default:
+		for _, kw := range []string{"oneOf", "anyOf", "allOf", "not"} {
+			if _, ok := schema[kw]; ok {
+				found(pointer, kw+" is not supported")
+				return
+			}
+		}
+		if raw, ok := schema["type"]; ok {
+			found(pointer, fmt.Sprintf("type %v is not supported", raw))
+		} else {
+			found(pointer, "schema has no type")
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
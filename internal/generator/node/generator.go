@@ -1,26 +1,37 @@
 package node
 
 import (
-	"encoding/json"
 	"fmt"
+	iofs "io/fs"
 	"os"
-	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"bakemcp/internal/domain/mapping"
 	"bakemcp/internal/domain/model"
 )
 
-// FS writes files to the filesystem (abstraction for tests).
+// FS reads and writes files of the generated project (abstraction for tests
+// and embedding). ReadFile follows io/fs.ReadFileFS: a missing file is an
+// error satisfying errors.Is(err, fs.ErrNotExist).
 type FS interface {
+	ReadFile(name string) ([]byte, error)
 	WriteFile(name string, data []byte, perm os.FileMode) error
 	MkdirAll(path string, perm os.FileMode) error
+	Remove(name string) error
 }
 
 // OsFS uses the real os package.
 type OsFS struct{}
 
+func (OsFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
 func (OsFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(name, data, perm)
 }
@@ -29,81 +40,187 @@ func (OsFS) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
 
+func (OsFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// File is a file of the generated project (or one written through a RecordingFS).
+type File struct {
+	Name      string
+	Data      []byte
+	Perm      os.FileMode
+	UserOwned bool // Created for the user to edit; kept when modified
+}
+
+// RecordingFS keeps written files in memory instead of writing them (dry runs).
+type RecordingFS struct {
+	Files   []File   // In write order
+	Removed []string // Removed paths, in order
+}
+
+// ReadFile returns the data last written to name, unless name was removed.
+func (r *RecordingFS) ReadFile(name string) ([]byte, error) {
+	for _, removed := range r.Removed {
+		if removed == name {
+			return nil, &iofs.PathError{Op: "read", Path: name, Err: iofs.ErrNotExist}
+		}
+	}
+	for i := len(r.Files) - 1; i >= 0; i-- {
+		if r.Files[i].Name == name {
+			return r.Files[i].Data, nil
+		}
+	}
+	return nil, &iofs.PathError{Op: "read", Path: name, Err: iofs.ErrNotExist}
+}
+
+func (r *RecordingFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	r.Files = append(r.Files, File{Name: name, Data: data, Perm: perm})
+	return nil
+}
+
+func (r *RecordingFS) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+func (r *RecordingFS) Remove(name string) error {
+	r.Removed = append(r.Removed, name)
+	return nil
+}
+
 // Generate writes a Node project to outDir with package.json and entry script
 // that registers one MCP tool per tool in tools.
 func Generate(outDir string, tools []*model.MCPTool, fs FS) error {
+	return GenerateServer(outDir, &model.MCPServer{Tools: tools}, fs)
+}
+
+// GenerateServer writes a Node project to outDir whose generated/tools.js
+// registers every tool, workflow, resource and prompt in srv. Existing files
+// are overwritten; use Plan and Apply to preserve user edits.
+func GenerateServer(outDir string, srv *model.MCPServer, fs FS) error {
+	files, err := Render(srv)
+	if err != nil {
+		return err
+	}
+	plan := make([]PlannedFile, 0, len(files))
+	for _, f := range files {
+		plan = append(plan, PlannedFile{File: f, Action: ActionOverwrite})
+	}
+	return Apply(outDir, plan, nil, fs)
+}
+
+// Generation is a rendered project and what writing it to a directory does.
+type Generation struct {
+	Plan     []PlannedFile
+	Manifest *Manifest     // Manifest of the previous run; nil if there is none
+	Report   *model.Report // What the project contains and every warning along the way
+}
+
+// PrepareOptions control Prepare.
+type PrepareOptions struct {
+	Templates *template.Template // Defaults to the built-in templates (see LoadTemplates)
+	Warnings  []model.Warning    // Warnings of earlier stages to include in the report
+	Report    bool               // Also render the report as ReportFile
+	Plan      PlanOptions        // How to treat modified files; Plan.FS also reads the manifest
+}
+
+// Prepare renders the project for srv (and its report) and plans writing it
+// to outDir against the manifest found there; pass the plan to Apply. The
+// report is optional: a run without it leaves the previous one in place.
+func Prepare(outDir string, srv *model.MCPServer, opts PrepareOptions) (*Generation, error) {
+	fs := opts.Plan.FS
 	if fs == nil {
 		fs = OsFS{}
 	}
-	if err := fs.MkdirAll(outDir, 0755); err != nil {
-		return err
+	prev, err := ReadManifest(fs, outDir)
+	if err != nil {
+		return nil, err
 	}
-	pkg := packageJSON(tools)
-	pkgPath := filepath.Join(outDir, "package.json")
-	pkgBytes, _ := json.MarshalIndent(pkg, "", "  ")
-	if err := fs.WriteFile(pkgPath, pkgBytes, 0644); err != nil {
-		return err
+	set := opts.Templates
+	if set == nil {
+		set = builtinTemplates
 	}
-	entry := entryScript(tools)
-	entryPath := filepath.Join(outDir, "index.js")
-	if err := fs.WriteFile(entryPath, []byte(entry), 0755); err != nil {
-		return err
+	report := BuildReport(srv, opts.Warnings)
+	files, err := RenderWith(srv, set)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render templates: %w", err)
 	}
-	return nil
+	if opts.Report {
+		files = append(files, RenderReport(report))
+	}
+	opts.Plan.Optional = append([]string{ReportFile}, opts.Plan.Optional...)
+	return &Generation{Plan: Plan(outDir, files, prev, opts.Plan), Manifest: prev, Report: report}, nil
 }
 
-func packageJSON(tools []*model.MCPTool) map[string]interface{} {
-	return map[string]interface{}{
-		"name":    "generated-mcp",
-		"version": "1.0.0",
-		"type":    "module",
-		"scripts": map[string]string{"start": "node index.js"},
-		"dependencies": map[string]string{
-			"fastmcp": "^3.29.0",
-			"zod":     "^3.23.0",
-		},
-	}
+// Files of a generated project, relative to the output directory.
+const (
+	PackageFile = "package.json"
+	EntryFile   = "index.js"
+	ToolsFile   = "generated/tools.js"
+)
+
+// Render returns the files of the Node project for srv, rendered from the
+// built-in templates: package.json and the index.js entry point are created
+// for the user to edit, generated/tools.js is regenerated on every run.
+func Render(srv *model.MCPServer) ([]File, error) {
+	return RenderWith(srv, builtinTemplates)
 }
 
-// ---------------------------------------------------------------------------
-// Entry script generation
-// ---------------------------------------------------------------------------
+// sourceBaseURL is the default base URL of one source (spec) in the server.
+type sourceBaseURL struct {
+	source     string
+	defaultURL string
+}
 
-func entryScript(tools []*model.MCPTool) string {
-	// Extract the base URL from the first tool (all share the same base).
-	defaultBaseURL := ""
-	if len(tools) > 0 {
-		defaultBaseURL = tools[0].BaseURL
-	}
-
-	var b strings.Builder
-	b.WriteString(`import { FastMCP } from "fastmcp";
-import { z } from "zod";
-
-`)
-	b.WriteString(fmt.Sprintf("const BASE_URL = process.env.BASE_URL || %q;\n", defaultBaseURL))
-	b.WriteString(`
-const server = new FastMCP({ name: "generated-mcp", version: "1.0.0" });
-`)
-	for _, t := range tools {
-		b.WriteString(toolBlock(t))
-	}
-	b.WriteString(`
-server.start({ transportType: "stdio" });
-`)
-	return b.String()
+// baseURLs returns one entry per distinct source in srv, in order of first
+// appearance. A server built from a single spec has one unnamed source.
+func baseURLs(srv *model.MCPServer) []sourceBaseURL {
+	var out []sourceBaseURL
+	seen := make(map[string]bool)
+	add := func(source, url string) {
+		if !seen[source] {
+			seen[source] = true
+			out = append(out, sourceBaseURL{source: source, defaultURL: url})
+		}
+	}
+	for _, t := range srv.Tools {
+		add(t.Source, t.BaseURL)
+	}
+	for _, w := range srv.Workflows {
+		for _, st := range w.Steps {
+			add(st.Tool.Source, st.Tool.BaseURL)
+		}
+	}
+	for _, r := range srv.Resources {
+		add(r.Source, r.BaseURL)
+	}
+	if len(out) == 0 {
+		add("", "")
+	}
+	return out
 }
 
-func toolBlock(t *model.MCPTool) string {
-	zodSchema := buildZodSchema(t)
-	executeFn := buildExecuteFn(t)
-	return fmt.Sprintf(`server.addTool({
-  name: %q,
-  description: %q,
-  parameters: %s,
-  execute: %s,
-});
-`, t.Name, t.Description, zodSchema, executeFn)
+// baseURLVar returns the JS constant (and env var) holding the base URL of
+// source: BASE_URL for a single spec, <SOURCE>_BASE_URL otherwise (see
+// model.EnvName).
+func baseURLVar(source string) string {
+	if source == "" {
+		return "BASE_URL"
+	}
+	return model.EnvName(source) + "_BASE_URL"
+}
+
+// checkBaseURLVars fails when two sources of srv would declare the same base
+// URL constant, which is a SyntaxError in tools.js.
+func checkBaseURLVars(srv *model.MCPServer) error {
+	owner := make(map[string]string)
+	for _, u := range baseURLs(srv) {
+		v := baseURLVar(u.source)
+		if other, ok := owner[v]; ok {
+			return fmt.Errorf("sources %q and %q both read their base URL from %s; give them distinct names", other, u.source, v)
+		}
+		owner[v] = u.source
+	}
+	return nil
 }
 
 // ---------------------------------------------------------------------------
@@ -119,12 +236,12 @@ type zodField struct {
 func buildZodSchema(t *model.MCPTool) string {
 	var fields []zodField
 
-	// Add params (path, query, header)
+	// Add params (path, query, header); the user is asked for elicited ones
 	for _, p := range t.Params {
 		fields = append(fields, zodField{
 			name:     p.Name,
 			zod:      schemaToZod(p.Schema, 6),
-			required: p.Required,
+			required: p.Required && !slices.Contains(t.Elicit, p.Name),
 		})
 	}
 
@@ -142,6 +259,23 @@ func buildZodSchema(t *model.MCPTool) string {
 		}
 	}
 
+	// The fields argument selects response fields; it is not sent
+	if t.Fields != nil {
+		fields = append(fields, zodField{name: mapping.ProjectionArgument, zod: "z.string()"})
+	}
+
+	// The confirm argument stands in for elicitation; it is not sent
+	if t.Confirm {
+		fields = append(fields, zodField{name: mapping.ConfirmArgument, zod: "z.boolean()"})
+	}
+
+	// The pagination arguments say how many pages to fetch; they are not sent
+	if t.Pagination != nil {
+		fields = append(fields,
+			zodField{name: mapping.FetchAllArgument, zod: "z.boolean()"},
+			zodField{name: mapping.MaxPagesArgument, zod: "z.number().int().min(1)"})
+	}
+
 	if len(fields) == 0 {
 		return "z.object({})"
 	}
@@ -157,7 +291,7 @@ func buildZodSchema(t *model.MCPTool) string {
 		if !f.required {
 			zod += ".optional()"
 		}
-		lines = append(lines, fmt.Sprintf("    %s: %s,", f.name, zod))
+		lines = append(lines, fmt.Sprintf("    %s: %s,", propKey(f.name), zod))
 	}
 	return fmt.Sprintf("z.object({\n%s\n  })", strings.Join(lines, "\n"))
 }
@@ -227,7 +361,7 @@ func objectToZod(schema map[string]interface{}, innerIndent int) string {
 		if !reqSet[name] {
 			zod += ".optional()"
 		}
-		lines = append(lines, fmt.Sprintf("%s%s: %s,", pad, name, zod))
+		lines = append(lines, fmt.Sprintf("%s%s: %s,", pad, propKey(name), zod))
 	}
 
 	return fmt.Sprintf("z.object({\n%s\n%s})", strings.Join(lines, "\n"), closePad)
@@ -246,101 +380,43 @@ func requiredSet(schema map[string]interface{}) map[string]bool {
 }
 
 // ---------------------------------------------------------------------------
-// Execute function generation
+// Expressions used by the templates
 // ---------------------------------------------------------------------------
 
 var pathParamRe = regexp.MustCompile(`\{([^}]+)\}`)
 
-func buildExecuteFn(t *model.MCPTool) string {
-	pathParams := filterByIn(t.Params, "path")
-	queryParams := filterByIn(t.Params, "query")
-	hasBody := t.Body != nil
-	hasPathParams := len(pathParams) > 0
-	hasQueryParams := len(queryParams) > 0
-	hasNonBodyParams := hasPathParams || hasQueryParams
-	needsArgs := hasBody || hasPathParams || hasQueryParams
-
-	var lines []string
-
-	// Destructure path/query params from body when both are present
-	useDestructuring := hasBody && hasNonBodyParams
-	if useDestructuring {
-		var names []string
-		for _, p := range pathParams {
-			names = append(names, p.Name)
-		}
-		for _, p := range queryParams {
-			names = append(names, p.Name)
-		}
-		lines = append(lines, fmt.Sprintf("    const { %s, ...bodyArgs } = args;", strings.Join(names, ", ")))
-	}
-
-	// URL construction
-	paramPrefix := "args."
-	if useDestructuring {
-		paramPrefix = ""
-	}
-	urlExpr := buildURLExpr(t.Path, pathParams, paramPrefix)
-
-	if hasQueryParams || hasPathParams {
-		lines = append(lines, fmt.Sprintf("    let url = %s;", urlExpr))
-	}
-
-	// Query params
-	if hasQueryParams {
-		lines = append(lines, "    const qp = new URLSearchParams();")
-		for _, q := range queryParams {
-			ref := paramPrefix + q.Name
-			lines = append(lines, fmt.Sprintf("    if (%s !== undefined) qp.append(%q, String(%s));", ref, q.Name, ref))
-		}
-		lines = append(lines, "    const qs = qp.toString();")
-		lines = append(lines, `    if (qs) url += "?" + qs;`)
-	}
-
-	// Fetch call
-	var fetchURL string
-	if hasQueryParams || hasPathParams {
-		fetchURL = "url"
-	} else {
-		fetchURL = urlExpr
-	}
+var jsIdentRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
 
-	if hasBody {
-		bodyRef := "args"
-		if useDestructuring {
-			bodyRef = "bodyArgs"
-		}
-		lines = append(lines, fmt.Sprintf("    const res = await fetch(%s, {", fetchURL))
-		lines = append(lines, fmt.Sprintf("      method: %q,", t.Method))
-		lines = append(lines, `      headers: { "Content-Type": "application/json" },`)
-		lines = append(lines, fmt.Sprintf("      body: JSON.stringify(%s),", bodyRef))
-		lines = append(lines, "    });")
-	} else {
-		lines = append(lines, fmt.Sprintf("    const res = await fetch(%s, { method: %q });", fetchURL, t.Method))
+// argExpr returns the JS expression reading the argument name from args:
+// args.name, or args["name"] when name is not an identifier.
+func argExpr(name string) string {
+	if jsIdentRe.MatchString(name) {
+		return "args." + name
 	}
+	return "args[" + strconv.Quote(name) + "]"
+}
 
-	lines = append(lines, "    const body = await res.text();")
-	lines = append(lines, `    if (!res.ok) throw new Error("HTTP " + res.status + ": " + body);`)
-	lines = append(lines, "    return body;")
-
-	argsStr := "()"
-	if needsArgs {
-		argsStr = "(args)"
+// propKey returns name as a JS object key, quoted when it is not an identifier.
+func propKey(name string) string {
+	if jsIdentRe.MatchString(name) {
+		return name
 	}
-	return fmt.Sprintf("async %s => {\n%s\n  }", argsStr, strings.Join(lines, "\n"))
+	return strconv.Quote(name)
 }
 
-func buildURLExpr(path string, pathParams []model.MCPToolParam, paramPrefix string) string {
+// buildURLExpr returns the JS expression for baseVar + path with every path
+// parameter read from args.
+func buildURLExpr(baseVar, path string, pathParams []model.MCPToolParam) string {
 	if len(pathParams) == 0 {
 		// No path params → simple string concatenation: BASE_URL + "/path"
-		return fmt.Sprintf("BASE_URL + %q", path)
+		return fmt.Sprintf("%s + %q", baseVar, path)
 	}
-	// Convert {param} to ${encodeURIComponent(prefix.param)} in template literal
+	// Convert {param} to ${encodeURIComponent(args.param)} in template literal
 	result := pathParamRe.ReplaceAllStringFunc(path, func(match string) string {
 		name := match[1 : len(match)-1] // strip { and }
-		return fmt.Sprintf("${encodeURIComponent(%s%s)}", paramPrefix, name)
+		return fmt.Sprintf("${encodeURIComponent(%s)}", argExpr(name))
 	})
-	return fmt.Sprintf("`${BASE_URL}%s`", result)
+	return fmt.Sprintf("`${%s}%s`", baseVar, result)
 }
 
 func filterByIn(params []model.MCPToolParam, in string) []model.MCPToolParam {
@@ -0,0 +1,229 @@
+package node
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ManifestFile records the hash of every file bakemcp wrote, relative to the
+// output directory, so regeneration can tell which files the user modified.
+const ManifestFile = ".bakemcp-manifest.json"
+
+// Manifest is the content of ManifestFile.
+type Manifest struct {
+	Files []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is one generated file and the SHA-256 of its content as written.
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// LoadManifest reads the manifest in outDir; it returns nil when there is none.
+func LoadManifest(outDir string) (*Manifest, error) {
+	return ReadManifest(OsFS{}, outDir)
+}
+
+// ReadManifest is LoadManifest reading through fs.
+func ReadManifest(fs FS, outDir string) (*Manifest, error) {
+	data, err := fs.ReadFile(filepath.Join(outDir, ManifestFile))
+	if err != nil {
+		if errors.Is(err, iofs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+// hash returns the recorded hash of path, if any.
+func (m *Manifest) hash(path string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	for _, e := range m.Files {
+		if e.Path == path {
+			return e.SHA256, true
+		}
+	}
+	return "", false
+}
+
+func sha(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Action is what regeneration does with one file.
+type Action string
+
+const (
+	ActionCreate    Action = "create"    // File does not exist yet
+	ActionUpdate    Action = "update"    // File is unmodified since the last run and changes
+	ActionUnchanged Action = "unchanged" // File already has the new content
+	ActionKeep      Action = "keep"      // User-owned file was modified; left as is
+	ActionConflict  Action = "conflict"  // Generated file was modified, or bakemcp did not write it; needs overwriting explicitly
+	ActionOverwrite Action = "overwrite" // Modified file is replaced on request
+	ActionDelete    Action = "delete"    // File is no longer generated and is removed
+	ActionDisown    Action = "disown"    // File is no longer generated but was modified; left to the user
+	ActionRetain    Action = "retain"    // Optional file not requested this run; left as is and still recorded
+)
+
+// PlannedFile is a file together with what regeneration does with it.
+type PlannedFile struct {
+	File
+	Action Action
+}
+
+// PlanOptions control how Plan treats files modified since the last run.
+type PlanOptions struct {
+	OverwriteModified bool     // Replace (or delete) modified files, including ones bakemcp did not record
+	Clean             bool     // Replace (or delete) every generated file recorded in the manifest, modified or not
+	Force             bool     // Replace files bakemcp did not record, e.g. from a run before manifests existed
+	Optional          []string // Recorded files a run may leave out (e.g. ReportFile); they are retained, not deleted
+	FS                FS       // Reads the existing files; defaults to OsFS
+}
+
+// Plan compares files with the content of outDir and the manifest prev. A file
+// counts as modified when its content differs from the recorded hash (or it
+// has none). Modified user-owned files are kept and modified generated files
+// are conflicts, unless opts say otherwise; Clean never replaces user-owned
+// files. A file bakemcp did not record is a conflict even when user-owned:
+// keeping a stale entry point would leave the new generated files unused.
+// Files recorded in prev that are no longer generated are deleted when
+// unmodified and disowned otherwise, except optional ones, which are retained;
+// files that are not recorded in prev (or lie outside outDir) are never deleted.
+func Plan(outDir string, files []File, prev *Manifest, opts PlanOptions) []PlannedFile {
+	fs := opts.FS
+	if fs == nil {
+		fs = OsFS{}
+	}
+	plan := make([]PlannedFile, 0, len(files))
+	generated := make(map[string]bool, len(files))
+	for _, f := range files {
+		generated[f.Name] = true
+		action := ActionCreate
+		if existing, err := fs.ReadFile(filepath.Join(outDir, f.Name)); err == nil {
+			recorded, ok := prev.hash(f.Name)
+			switch {
+			case bytes.Equal(existing, f.Data):
+				action = ActionUnchanged
+			case ok && recorded == sha(existing):
+				action = ActionUpdate
+			case opts.OverwriteModified || (opts.Clean && ok && !f.UserOwned) || (opts.Force && !ok):
+				action = ActionOverwrite
+			case f.UserOwned && ok:
+				action = ActionKeep
+			default:
+				action = ActionConflict
+			}
+		}
+		plan = append(plan, PlannedFile{File: f, Action: action})
+	}
+	if prev == nil {
+		return plan
+	}
+	for _, e := range prev.Files {
+		if generated[e.Path] || !filepath.IsLocal(e.Path) {
+			continue
+		}
+		existing, err := fs.ReadFile(filepath.Join(outDir, e.Path))
+		if err != nil {
+			continue
+		}
+		action := ActionDisown
+		switch {
+		case slices.Contains(opts.Optional, e.Path):
+			action = ActionRetain
+		case sha(existing) == e.SHA256 || opts.OverwriteModified || opts.Clean:
+			action = ActionDelete
+		}
+		plan = append(plan, PlannedFile{File: File{Name: e.Path}, Action: action})
+	}
+	return plan
+}
+
+// Conflicts returns the names of the files Apply would refuse to write.
+func Conflicts(plan []PlannedFile) []string {
+	var out []string
+	for _, p := range plan {
+		if p.Action == ActionConflict {
+			out = append(out, p.Name)
+		}
+	}
+	return out
+}
+
+// Apply writes the created, updated and overwritten files of plan to outDir,
+// removes deleted files (and directories they leave empty) and records the
+// written files in a new manifest. Kept and retained files keep their entry
+// from prev; disowned files are dropped from it. It fails without writing
+// anything if plan has conflicts.
+func Apply(outDir string, plan []PlannedFile, prev *Manifest, fs FS) error {
+	if fs == nil {
+		fs = OsFS{}
+	}
+	if c := Conflicts(plan); len(c) > 0 {
+		return fmt.Errorf("modified files would be overwritten: %v", c)
+	}
+	if err := fs.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	m := &Manifest{}
+	for _, p := range plan {
+		path := filepath.Join(outDir, p.Name)
+		switch p.Action {
+		case ActionDisown:
+			continue
+		case ActionDelete:
+			if err := fs.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			removeEmptyDirs(fs, outDir, filepath.Dir(path))
+			continue
+		case ActionKeep, ActionRetain:
+			if h, ok := prev.hash(p.Name); ok {
+				m.Files = append(m.Files, ManifestEntry{Path: p.Name, SHA256: h})
+			}
+			continue
+		case ActionUnchanged:
+		default:
+			if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := fs.WriteFile(path, p.Data, p.Perm); err != nil {
+				return err
+			}
+		}
+		m.Files = append(m.Files, ManifestEntry{Path: p.Name, SHA256: sha(p.Data)})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	data, _ := json.MarshalIndent(m, "", "  ")
+	return fs.WriteFile(filepath.Join(outDir, ManifestFile), append(data, '\n'), 0644)
+}
+
+// removeEmptyDirs removes dir and its parents below outDir while they are
+// empty; removing a non-empty directory fails and stops the walk.
+func removeEmptyDirs(fs FS, outDir, dir string) {
+	for dir != outDir && strings.HasPrefix(dir, outDir+string(filepath.Separator)) {
+		if fs.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,46 @@
+package node
+
+import (
+	"encoding/json"
+	"sort"
+
+	"bakemcp/internal/domain/model"
+)
+
+// ReportFile is the name of the coverage report written next to the project.
+const ReportFile = "bakemcp-report.json"
+
+// BuildReport counts what srv contains and collects the warnings of every
+// stage (parseWarnings, mapping and Check), ordered by source and JSON pointer.
+func BuildReport(srv *model.MCPServer, parseWarnings []model.Warning) *model.Report {
+	warnings := append([]model.Warning{}, parseWarnings...)
+	for _, t := range srv.Tools {
+		warnings = append(warnings, t.Warnings...)
+	}
+	for _, r := range srv.Resources {
+		warnings = append(warnings, r.Warnings...)
+	}
+	warnings = append(warnings, Check(srv)...)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Source != warnings[j].Source {
+			return warnings[i].Source < warnings[j].Source
+		}
+		return warnings[i].Pointer < warnings[j].Pointer
+	})
+	return &model.Report{
+		Tools:     len(srv.Tools),
+		Resources: len(srv.Resources),
+		Prompts:   len(srv.Prompts),
+		Workflows: len(srv.Workflows),
+		Warnings:  warnings,
+	}
+}
+
+// RenderReport renders r as the JSON ReportFile.
+func RenderReport(r *model.Report) File {
+	if r.Warnings == nil {
+		r.Warnings = []model.Warning{}
+	}
+	data, _ := json.MarshalIndent(r, "", "  ")
+	return File{Name: ReportFile, Data: append(data, '\n'), Perm: 0644}
+}
@@ -0,0 +1,421 @@
+package node
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+	"bakemcp/templates"
+)
+
+// Templates rendered to the files of the project; every other template is a
+// partial included by them. Names are slash-separated paths relative to the
+// template directory.
+var projectTemplates = []struct {
+	file      string
+	template  string
+	perm      os.FileMode
+	userOwned bool
+}{
+	{PackageFile, "package.json.tmpl", 0644, true},
+	{EntryFile, "index.js.tmpl", 0755, true},
+	{ToolsFile, "generated/tools.js.tmpl", 0644, false},
+}
+
+// TemplateData is what the templates render. tools.js and the partials it
+// includes receive the entry of the tool, workflow, resource or prompt they
+// register; the project templates receive the whole TemplateData.
+type TemplateData struct {
+	Server       *model.MCPServer // The mapped server, as is
+	Name         string           // Server and npm package name; defaults to generated-mcp
+	Version      string           // Server and package version; defaults to 1.0.0
+	Instructions string           // Guidance sent to clients when they connect; may be empty
+	BaseURLs     []BaseURL
+	RateLimit    RateLimitOptions             // Global limits; the environment overrides them
+	RateLimits   map[string]*RateLimitOptions // Limits of single operations, by RequestOptions.LimitKey
+	Tools        []*ToolData
+	Projection   bool // Some tool has a fields argument: tools.js includes the projection runtime
+	Paging       bool // Some tool is paginated: tools.js includes the pagination runtime
+	Polling      bool // Some tool polls a 202 Accepted operation: tools.js includes the async runtime
+	Streaming    bool // Some tool reads a streamed response: tools.js includes the stream runtime
+	Elicits      bool // Some tool asks the user for confirmation or arguments: tools.js includes the elicitation runtime
+	Workflows    []*WorkflowData
+	Resources    []*ResourceData
+	Prompts      []*model.MCPPrompt
+}
+
+// BaseURL is one base URL constant of tools.js.
+type BaseURL struct {
+	Var     string // JS constant and environment variable, e.g. BASE_URL or SHOP_BASE_URL
+	Default string // First server URL of the spec
+}
+
+// ToolData is a mapped tool plus the JS expressions derived from it.
+type ToolData struct {
+	*model.MCPTool
+	Schema      string                 // zod expression for the arguments, e.g. z.object({ ... })
+	PathParams  []model.MCPToolParam   // Params sent in the path
+	QueryParams []model.MCPToolParam   // Params sent in the query string
+	URLParams   []model.MCPToolParam   // PathParams followed by QueryParams
+	Destructure bool                   // The tool has a body and URL params: bodyArgs holds args without the URL params
+	URL         string                 // JS expression for the request URL, path parameters filled in
+	Request     *RequestOptions        // Per-tool request settings; nil keeps the runtime defaults
+	MaxResponse int                    // Characters of a response passed on; 0 keeps the runtime default
+	Hint        string                 // Tells the model how to ask for less when a response is truncated
+	Paging      *PagingOptions         // How fetchPages follows the pages of the response; nil if not paginated
+	Polling     *PollingOptions        // How sendAndPoll waits for a 202 Accepted operation; nil if it is not async
+	Elicitation map[string]interface{} // Elicitation schema of the parameters in .Elicit (render with json); nil if none
+}
+
+// PollingOptions is how one tool waits for an asynchronous operation, as read
+// by sendAndPoll in partials/async-runtime.js.tmpl (render with json).
+type PollingOptions struct {
+	StatusURL  string `json:"statusUrl,omitempty"`
+	IntervalMs int    `json:"intervalMs,omitempty"`
+	TimeoutMs  int    `json:"timeoutMs,omitempty"`
+}
+
+// PagingOptions is the pagination of one tool as read by fetchPages in
+// partials/pagination-runtime.js.tmpl (render with json).
+type PagingOptions struct {
+	Style      string `json:"style"`
+	Param      string `json:"param,omitempty"`
+	SizeParam  string `json:"sizeParam,omitempty"`
+	Start      int    `json:"start"`
+	Items      string `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// RequestOptions are the request settings of one tool as passed to the HTTP
+// helper in partials/http-runtime.js.tmpl (render with json).
+type RequestOptions struct {
+	TimeoutMs  int    `json:"timeoutMs,omitempty"`
+	Retries    *int   `json:"retries,omitempty"`
+	Idempotent bool   `json:"idempotent,omitempty"`
+	LimitKey   string `json:"limitKey,omitempty"` // Key of the operation's entry in TemplateData.RateLimits
+	Stream     bool   `json:"stream,omitempty"`   // Return a successful response unread, for readStream
+}
+
+// RateLimitOptions are limits as read by partials/limits-runtime.js.tmpl
+// (render with json); zero fields are unlimited.
+type RateLimitOptions struct {
+	Limit       int `json:"limit,omitempty"`
+	WindowMs    int `json:"windowMs,omitempty"`
+	Burst       int `json:"burst,omitempty"`
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// requestOptions returns the options of h for the operation method path of
+// source, or nil if h keeps every default.
+func requestOptions(h model.MCPToolHTTP, source, method, path string) *RequestOptions {
+	o := &RequestOptions{TimeoutMs: h.TimeoutMs, Retries: h.Retries, Idempotent: h.Idempotent}
+	if h.RateLimit != nil {
+		o.LimitKey = limitKey(source, method, path)
+	}
+	if *o == (RequestOptions{}) {
+		return nil
+	}
+	return o
+}
+
+// limitKey identifies an operation across the tools, resources and workflow
+// steps calling it, which share its limits: "GET /items", or
+// "billing GET /items" for a source of a merged server.
+func limitKey(source, method, path string) string {
+	return strings.TrimSpace(source + " " + strings.ToUpper(method) + " " + path)
+}
+
+func rateLimitOptions(rl *model.MCPRateLimit) *RateLimitOptions {
+	return &RateLimitOptions{Limit: rl.Limit, WindowMs: rl.WindowMs, Burst: rl.Burst, Concurrency: rl.Concurrency}
+}
+
+// WorkflowData is a composite tool plus the JS expressions derived from it.
+type WorkflowData struct {
+	*model.MCPWorkflow
+	Schema string // zod expression for the workflow inputs
+	Steps  []*WorkflowStepData
+}
+
+// WorkflowStepData is one step of a workflow.
+type WorkflowStepData struct {
+	model.MCPWorkflowStep
+	BaseURLVar string          // JS constant holding the base URL of the step's operation
+	Parameters []WorkflowParam // Params in the form runWorkflow expects (render with json)
+	HasBody    bool            // The step sends Body
+	Request    *RequestOptions // Request settings of the step's operation; nil keeps the defaults
+}
+
+// WorkflowParam is a workflow step parameter as passed to runWorkflow.
+type WorkflowParam struct {
+	Name  string      `json:"name"`
+	In    string      `json:"in"`
+	Value interface{} `json:"value"`
+}
+
+// ResourceData is a mapped resource plus the JS expressions derived from it.
+type ResourceData struct {
+	*model.MCPResource
+	URL         string          // JS expression for the request URL, template arguments filled in
+	Request     *RequestOptions // Request settings of the operation; nil keeps the defaults
+	MaxResponse int             // Characters of a response passed on; 0 keeps the runtime default
+}
+
+// NewTemplateData derives the data the templates render from srv.
+func NewTemplateData(srv *model.MCPServer) *TemplateData {
+	d := &TemplateData{
+		Server:       srv,
+		Name:         srv.Name,
+		Version:      srv.Version,
+		Instructions: srv.Instructions,
+		Prompts:      srv.Prompts,
+	}
+	if d.Name == "" {
+		d.Name = mapping.DefaultServerName
+	}
+	if d.Version == "" {
+		d.Version = mapping.DefaultServerVersion
+	}
+	d.RateLimit.WindowMs = 1000
+	if srv.RateLimit != nil {
+		d.RateLimit = *rateLimitOptions(srv.RateLimit)
+	}
+	addLimit := func(h model.MCPToolHTTP, source, method, path string) {
+		if h.RateLimit == nil {
+			return
+		}
+		if d.RateLimits == nil {
+			d.RateLimits = make(map[string]*RateLimitOptions)
+		}
+		d.RateLimits[limitKey(source, method, path)] = rateLimitOptions(h.RateLimit)
+	}
+	for _, u := range baseURLs(srv) {
+		d.BaseURLs = append(d.BaseURLs, BaseURL{Var: baseURLVar(u.source), Default: u.defaultURL})
+	}
+	for _, t := range srv.Tools {
+		addLimit(t.HTTP, t.Source, t.Method, t.Path)
+		d.Tools = append(d.Tools, toolData(t))
+		d.Projection = d.Projection || t.Fields != nil
+		d.Paging = d.Paging || t.Pagination != nil
+		d.Polling = d.Polling || t.Async != nil
+		d.Streaming = d.Streaming || t.Stream != ""
+		d.Elicits = d.Elicits || t.Confirm || len(t.Elicit) > 0
+	}
+	for _, w := range srv.Workflows {
+		for _, st := range w.Steps {
+			addLimit(st.Tool.HTTP, st.Tool.Source, st.Tool.Method, st.Tool.Path)
+		}
+		d.Workflows = append(d.Workflows, workflowData(w))
+	}
+	for _, r := range srv.Resources {
+		addLimit(r.HTTP, r.Source, "GET", r.Path)
+		d.Resources = append(d.Resources, &ResourceData{
+			MCPResource: r,
+			URL:         buildURLExpr(baseURLVar(r.Source), r.Path, r.Params),
+			Request:     requestOptions(r.HTTP, r.Source, "GET", r.Path),
+			MaxResponse: r.HTTP.MaxResponse,
+		})
+	}
+	return d
+}
+
+func toolData(t *model.MCPTool) *ToolData {
+	d := &ToolData{
+		MCPTool:     t,
+		Schema:      buildZodSchema(t),
+		PathParams:  filterByIn(t.Params, "path"),
+		QueryParams: filterByIn(t.Params, "query"),
+		Request:     requestOptions(t.HTTP, t.Source, t.Method, t.Path),
+		MaxResponse: t.HTTP.MaxResponse,
+		Hint:        responseHint(t),
+	}
+	if p := t.Pagination; p != nil {
+		d.Paging = &PagingOptions{Style: p.Style, Param: p.Param, SizeParam: p.SizeParam, Start: p.Start, Items: p.Items, NextCursor: p.NextCursor}
+	}
+	if t.Stream != "" {
+		if d.Request == nil {
+			d.Request = &RequestOptions{}
+		}
+		d.Request.Stream = true
+	}
+	if len(t.Elicit) > 0 {
+		d.Elicitation = elicitationSchema(t)
+	}
+	if a := t.Async; a != nil {
+		d.Polling = &PollingOptions{StatusURL: a.StatusURL, IntervalMs: a.IntervalMs, TimeoutMs: a.TimeoutMs}
+	}
+	d.URLParams = append(append([]model.MCPToolParam{}, d.PathParams...), d.QueryParams...)
+	if len(d.URLParams) == 0 {
+		d.URLParams = nil
+	}
+	if t.Body != nil && d.URLParams != nil {
+		d.Destructure = true
+	}
+	d.URL = buildURLExpr(baseURLVar(t.Source), t.Path, d.PathParams)
+	return d
+}
+
+// responseHint suggests the optional query parameters of t to narrow a
+// response that was too large.
+func responseHint(t *model.MCPTool) string {
+	var names []string
+	for _, p := range t.Params {
+		if p.In == "query" && !p.Required {
+			names = append(names, p.Name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "Narrow the request to get a smaller response."
+	case 1:
+		return "Use the " + names[0] + " argument to get a smaller response."
+	}
+	return "Use the " + strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1] + " arguments to get a smaller response."
+}
+
+// elicitationSchema returns the MCP elicitation schema asking for the
+// parameters in t.Elicit: an enum becomes a choice, a string keeps its format
+// when elicitation supports it, and numbers keep their bounds.
+func elicitationSchema(t *model.MCPTool) map[string]interface{} {
+	props := make(map[string]interface{})
+	for _, p := range t.Params {
+		if !slices.Contains(t.Elicit, p.Name) {
+			continue
+		}
+		prop := map[string]interface{}{"type": "string", "title": p.Name}
+		if d, ok := p.Schema["description"].(string); ok && d != "" {
+			prop["description"] = d
+		}
+		switch typ, _ := p.Schema["type"].(string); {
+		case p.Schema["enum"] != nil:
+			enum, _ := p.Schema["enum"].([]interface{})
+			values := make([]string, 0, len(enum))
+			for _, v := range enum {
+				values = append(values, fmt.Sprint(v))
+			}
+			prop["enum"] = values
+		case typ == "number" || typ == "integer" || typ == "boolean":
+			prop["type"] = typ
+			for _, k := range []string{"minimum", "maximum"} {
+				if v, ok := p.Schema[k]; ok && typ != "boolean" {
+					prop[k] = v
+				}
+			}
+		default:
+			if f, _ := p.Schema["format"].(string); elicitationFormats[f] {
+				prop["format"] = f
+			}
+			for _, k := range []string{"minLength", "maxLength"} {
+				if v, ok := p.Schema[k]; ok {
+					prop[k] = v
+				}
+			}
+		}
+		props[p.Name] = prop
+	}
+	return map[string]interface{}{"type": "object", "properties": props, "required": t.Elicit}
+}
+
+// elicitationFormats are the string formats MCP elicitation supports.
+var elicitationFormats = map[string]bool{"email": true, "uri": true, "date": true, "date-time": true}
+
+// LoadTemplates returns the built-in templates with every *.tmpl file under
+// dir replacing the template of the same relative path (or adding a partial).
+// An empty dir returns the built-in templates.
+func LoadTemplates(dir string) (*template.Template, error) {
+	sources := make(map[string]string)
+	builtin, _ := iofs.Sub(templates.NodeFastMCP, "node-fastmcp")
+	if err := readTemplates(builtin, sources); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+		if err := readTemplates(os.DirFS(dir), sources); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var set *template.Template
+	set = template.New("").Funcs(template.FuncMap{
+		"quote":  strconv.Quote,
+		"json":   jsonLiteral,
+		"indent": indent,
+		"arg":    argExpr,
+		"include": func(name string, data interface{}) (string, error) {
+			var b strings.Builder
+			err := set.ExecuteTemplate(&b, name, data)
+			return b.String(), err
+		},
+	})
+	for _, name := range names {
+		if _, err := set.New(name).Parse(sources[name]); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// readTemplates adds every *.tmpl file of fsys to sources, keyed by its path.
+func readTemplates(fsys iofs.FS, sources map[string]string) error {
+	return iofs.WalkDir(fsys, ".", func(name string, d iofs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".tmpl" {
+			return err
+		}
+		data, err := iofs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sources[filepath.ToSlash(name)] = string(data)
+		return nil
+	})
+}
+
+// builtinTemplates are the embedded templates, parsed once.
+var builtinTemplates = template.Must(LoadTemplates(""))
+
+// RenderWith is Render using the templates set (see LoadTemplates).
+func RenderWith(srv *model.MCPServer, set *template.Template) ([]File, error) {
+	if err := checkBaseURLVars(srv); err != nil {
+		return nil, err
+	}
+	data := NewTemplateData(srv)
+	files := make([]File, 0, len(projectTemplates))
+	for _, pt := range projectTemplates {
+		var b strings.Builder
+		if err := set.ExecuteTemplate(&b, pt.template, data); err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: pt.file, Data: []byte(b.String()), Perm: pt.perm, UserOwned: pt.userOwned})
+	}
+	return files, nil
+}
+
+// indent prefixes every non-empty line of s with pad.
+func indent(pad, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = pad + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+package node
+
+import (
+	"encoding/json"
+
+	"bakemcp/internal/domain/model"
+)
+
+// workflowData derives the template data of w; the runWorkflow runtime in
+// partials/workflow-runtime.js.tmpl executes the steps.
+func workflowData(w *model.MCPWorkflow) *WorkflowData {
+	d := &WorkflowData{MCPWorkflow: w, Schema: "z.object({})"}
+	if w.InputSchema != nil {
+		d.Schema = objectToZod(w.InputSchema, 4)
+	}
+	for _, st := range w.Steps {
+		params := make([]WorkflowParam, 0, len(st.Params))
+		for _, p := range st.Params {
+			params = append(params, WorkflowParam{Name: p.Name, In: p.In, Value: p.Value})
+		}
+		d.Steps = append(d.Steps, &WorkflowStepData{
+			MCPWorkflowStep: st,
+			BaseURLVar:      baseURLVar(st.Tool.Source),
+			Parameters:      params,
+			HasBody:         st.Body != nil,
+			Request:         requestOptions(st.Tool.HTTP, st.Tool.Source, st.Tool.Method, st.Tool.Path),
+		})
+	}
+	return d
+}
+
+// jsonLiteral renders v as a JSON value usable as a JS literal; nil maps
+// become {} and nil string slices []. Map keys are sorted by encoding/json
+// for deterministic output.
+func jsonLiteral(v interface{}) string {
+	if m, ok := v.(map[string]string); ok && m == nil {
+		return "{}"
+	}
+	if l, ok := v.([]string); ok && l == nil {
+		return "[]"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "null"
+	}
+	return string(b)
+}
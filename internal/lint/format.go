@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Write renders findings for the spec at source in the given format.
+func Write(w io.Writer, format string, findings []Finding, source string) error {
+	switch format {
+	case "text", "":
+		return writeText(w, findings, source)
+	case "json":
+		return writeJSON(w, findings)
+	case "sarif":
+		return writeSARIF(w, findings, source)
+	}
+	return fmt.Errorf("unknown format %q (use text, json or sarif)", format)
+}
+
+func writeText(w io.Writer, findings []Finding, source string) error {
+	counts := make(map[Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+		loc := source
+		if f.Pointer != "" {
+			loc += "#" + f.Pointer
+		}
+		if f.Operation != "" {
+			fmt.Fprintf(w, "%-7s %s: %s (%s)\n  at %s\n", f.Severity, f.Rule, f.Message, f.Operation, loc)
+		} else {
+			fmt.Fprintf(w, "%-7s %s: %s\n  at %s\n", f.Severity, f.Rule, f.Message, loc)
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings, %d info\n", counts[Error], counts[Warning], counts[Info])
+	return err
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{"findings": findings})
+}
+
+// SARIF 2.1.0 types (only the fields bakemcp emits).
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+func writeSARIF(w io.Writer, findings []Finding, source string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "bakemcp",
+			InformationURI: "https://github.com/stefanoMat/bakemcp",
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range Rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: r.ID, ShortDescription: sarifMessage{Text: r.Description}})
+	}
+	for _, f := range findings {
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: source}}}
+		if f.Pointer != "" {
+			loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.Pointer}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.Rule,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,259 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/domain/openapi"
+)
+
+// Severity ranks findings; higher is more severe.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// MarshalText encodes the severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses error, warning or info.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "error":
+		return Error, nil
+	case "warning":
+		return Warning, nil
+	case "info":
+		return Info, nil
+	}
+	return Info, fmt.Errorf("unknown severity %q (use error, warning or info)", name)
+}
+
+// Rule describes one check.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Rules lists every check run by Lint.
+var Rules = []Rule{
+	{"openapi-invalid", Error, "Document does not parse or validate as OpenAPI 3.x"},
+	{"operation-id-missing", Warning, "Operation has no operationId; the tool name is derived from method and path"},
+	{"description-missing", Warning, "Operation has no summary or description; agents only see method and path"},
+	{"body-unmappable", Warning, "Request body has no JSON object schema; generated tools cannot send it"},
+	{"parameter-unsupported", Warning, "Header and cookie parameters are not sent by generated tools"},
+	{"content-type-unsupported", Warning, "Binary responses are returned to agents as text"},
+	{"tool-name-collision", Warning, "Tool name collides or has a numeric suffix and is renamed"},
+	{"schema-too-large", Warning, "Tool input schema exceeds the size limit and may crowd the agent's context"},
+	{"extension-invalid", Warning, "An x-mcp-* or x-ratelimit extension has an invalid value and is ignored"},
+}
+
+func ruleSeverity(id string) Severity {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r.Severity
+		}
+	}
+	return Info
+}
+
+// Finding is one problem found in a spec.
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Operation string   `json:"operation,omitempty"` // e.g. GET /products
+	Pointer   string   `json:"pointer,omitempty"`   // JSON pointer into the spec
+}
+
+// Options tune the checks.
+type Options struct {
+	MaxSchemaBytes int    // Input schema size above which schema-too-large fires; 0 uses DefaultMaxSchemaBytes
+	Path           string // File data was read from; local $refs resolve against it, as in generation
+}
+
+// DefaultMaxSchemaBytes is the default schema-too-large threshold.
+const DefaultMaxSchemaBytes = 16 * 1024
+
+// Lint validates the OpenAPI document in data and reports MCP-specific
+// problems. Documents that fail to load yield a single openapi-invalid finding.
+func Lint(data []byte, opts Options) []Finding {
+	if opts.MaxSchemaBytes <= 0 {
+		opts.MaxSchemaBytes = DefaultMaxSchemaBytes
+	}
+	doc, err := openapi.LoadAt(bytes.NewReader(data), opts.Path)
+	if err != nil {
+		return []Finding{newFinding("openapi-invalid", err.Error(), "", "")}
+	}
+
+	var out []Finding
+	if err := openapi.Validate(doc); err != nil {
+		out = append(out, newFinding("openapi-invalid", err.Error(), "", ""))
+	}
+	out = append(out, operationFindings(doc)...)
+	out = append(out, toolFindings(data, opts)...)
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Severity != out[j].Severity {
+			return out[i].Severity > out[j].Severity
+		}
+		if out[i].Pointer != out[j].Pointer {
+			return out[i].Pointer < out[j].Pointer
+		}
+		return out[i].Rule < out[j].Rule
+	})
+	return out
+}
+
+// Failed reports whether any finding is at least as severe as threshold.
+func Failed(findings []Finding, threshold Severity) bool {
+	for _, f := range findings {
+		if f.Severity >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func newFinding(rule, msg, operation, pointer string) Finding {
+	return Finding{Rule: rule, Severity: ruleSeverity(rule), Message: msg, Operation: operation, Pointer: pointer}
+}
+
+// operationFindings checks each operation in the raw document.
+func operationFindings(doc *openapi3.T) []Finding {
+	var out []Finding
+	for _, path := range doc.Paths.InMatchingOrder() {
+		item := doc.Paths.Value(path)
+		if item == nil {
+			continue
+		}
+		for method, op := range item.Operations() {
+			if op == nil {
+				continue
+			}
+			opName := method + " " + path
+			ptr := model.OperationPointer(path, method)
+			if op.OperationID == "" {
+				out = append(out, newFinding("operation-id-missing", "operation has no operationId", opName, ptr))
+			}
+			if op.Summary == "" && op.Description == "" {
+				out = append(out, newFinding("description-missing", "operation has no summary or description", opName, ptr))
+			}
+			for i, p := range op.Parameters {
+				if p == nil || p.Value == nil {
+					continue
+				}
+				if p.Value.In == "header" || p.Value.In == "cookie" {
+					out = append(out, newFinding("parameter-unsupported",
+						fmt.Sprintf("%s parameter %q is not sent by generated tools", p.Value.In, p.Value.Name),
+						opName, fmt.Sprintf("%s/parameters/%d", ptr, i)))
+				}
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				if msg := bodyProblem(op.RequestBody.Value); msg != "" {
+					out = append(out, newFinding("body-unmappable", msg, opName, ptr+"/requestBody"))
+				}
+			}
+			if op.Responses != nil {
+				for status, resp := range op.Responses.Map() {
+					if resp == nil || resp.Value == nil {
+						continue
+					}
+					for ct := range resp.Value.Content {
+						if isBinary(ct) {
+							out = append(out, newFinding("content-type-unsupported",
+								fmt.Sprintf("response %s has binary content type %s", status, ct),
+								opName, fmt.Sprintf("%s/responses/%s/content/%s", ptr, model.PointerEscape(status), model.PointerEscape(ct))))
+						}
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// bodyProblem explains why a request body cannot be mapped, or returns "".
+func bodyProblem(body *openapi3.RequestBody) string {
+	ct := body.Content.Get("application/json")
+	if ct == nil {
+		var types []string
+		for t := range body.Content {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		return fmt.Sprintf("request body has no application/json content (found %s)", strings.Join(types, ", "))
+	}
+	if ct.Schema == nil || ct.Schema.Value == nil {
+		return "JSON request body has no schema"
+	}
+	if len(ct.Schema.Value.Properties) == 0 {
+		return "JSON request body schema has no object properties to map to tool arguments"
+	}
+	return ""
+}
+
+func isBinary(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	return ct == "application/octet-stream" || ct == "application/pdf" || ct == "application/zip" ||
+		strings.HasPrefix(ct, "image/") || strings.HasPrefix(ct, "audio/") || strings.HasPrefix(ct, "video/")
+}
+
+// toolFindings maps the operations to tools and checks names and schema sizes.
+func toolFindings(data []byte, opts Options) []Finding {
+	result, err := openapi.ParseAt(bytes.NewReader(data), opts.Path)
+	if err != nil {
+		return nil
+	}
+	tools := mapping.Build(result.Operations, result.BaseURL, mapping.BuildOptions{})
+
+	var out []Finding
+	_, limitWarnings := mapping.DocumentRateLimit(result.Extensions)
+	for _, w := range limitWarnings {
+		out = append(out, newFinding("extension-invalid", w.Message, "", w.Pointer))
+	}
+	for i, t := range tools {
+		op := result.Operations[i]
+		opName := strings.ToUpper(op.Method) + " " + op.Path
+		ptr := model.OperationPointer(op.Path, op.Method)
+		if natural := mapping.OperationToMCPTool(op, "").Name; natural != t.Name {
+			out = append(out, newFinding("tool-name-collision",
+				fmt.Sprintf("tool %q is renamed to %q", natural, t.Name), opName, ptr))
+		}
+		for _, w := range t.Warnings {
+			if strings.HasPrefix(w.Pointer, ptr+"/x-") {
+				out = append(out, newFinding("extension-invalid", w.Message, opName, w.Pointer))
+			}
+		}
+		schema, _ := json.Marshal(t.InputSchema)
+		if len(schema) > opts.MaxSchemaBytes {
+			out = append(out, newFinding("schema-too-large",
+				fmt.Sprintf("tool %s input schema is %d bytes (limit %d)", t.Name, len(schema), opts.MaxSchemaBytes), opName, ptr))
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+// Package bakemcp turns OpenAPI 3.x documents into MCP servers. It is the
+// library behind the bakemcp command: Parse reads a spec, Map turns its
+// operations into MCP tools (and optionally resources, prompts and
+// workflows), and Generate writes the Node project for the result.
+//
+//	spec, err := bakemcp.ParseFile("api.yaml", bakemcp.ParseOptions{})
+//	srv, err := bakemcp.Map(spec, bakemcp.MapOptions{})
+//	report, err := bakemcp.Generate("out", srv, bakemcp.GenerateOptions{Warnings: spec.Warnings})
+package bakemcp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"bakemcp/internal/domain/arazzo"
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/domain/openapi"
+	"bakemcp/internal/domain/overlay"
+)
+
+// Types of the parsed spec and the mapped server.
+type (
+	Spec            = openapi.ParseResult // Operations, base URL, tags and parse warnings of one document
+	Operation       = model.Operation
+	Parameter       = model.Parameter
+	RequestBody     = model.RequestBody
+	Link            = model.Link
+	Source          = model.Source
+	Tag             = model.Tag
+	Info            = model.Info
+	Server          = model.MCPServer
+	Tool            = model.MCPTool
+	ToolParam       = model.MCPToolParam
+	ToolBody        = model.MCPToolBody
+	ToolAnnotations = model.MCPToolAnnotations
+	ToolHTTP        = model.MCPToolHTTP
+	RateLimit       = model.MCPRateLimit
+	Pagination      = model.MCPPagination
+	Async           = model.MCPAsync
+	Resource        = model.MCPResource
+	Prompt          = model.MCPPrompt
+	Workflow        = model.MCPWorkflow
+	WorkflowStep    = model.MCPWorkflowStep
+	WorkflowParam   = model.MCPWorkflowParam
+	Warning         = model.Warning // Something a stage could not express, with a JSON pointer into the spec
+	Report          = model.Report  // What Generate produced and every warning along the way
+)
+
+// ErrOpenAPI2Unsupported is returned by Parse for OpenAPI 2.0 (Swagger) documents.
+var ErrOpenAPI2Unsupported = openapi.ErrOpenAPI2Unsupported
+
+// ParseOptions control Parse.
+type ParseOptions struct {
+	Path    string    // Location of the document; $refs to local files resolve relative to it
+	Overlay io.Reader // OpenAPI Overlay document applied before parsing (optional)
+}
+
+// Parse reads an OpenAPI 3.x document (YAML or JSON) from r.
+func Parse(r io.Reader, opts ParseOptions) (*Spec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Overlay != nil {
+		ov, err := overlay.Parse(opts.Overlay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overlay: %w", err)
+		}
+		if data, err = overlay.Apply(data, ov); err != nil {
+			return nil, fmt.Errorf("cannot apply overlay: %w", err)
+		}
+	}
+	return openapi.ParseAt(bytes.NewReader(data), opts.Path)
+}
+
+// ParseFile is Parse for the document at path; opts.Path defaults to path.
+func ParseFile(path string, opts ParseOptions) (*Spec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if opts.Path == "" {
+		opts.Path = path
+	}
+	return Parse(f, opts)
+}
+
+// NamedSpec is one spec of a server built from several: Name identifies its
+// source (base URL environment variable, warnings) and Prefix, when set, is
+// prepended to its tool names.
+type NamedSpec struct {
+	Name   string
+	Prefix string
+	Spec   *Spec
+}
+
+// Merge combines several parsed specs into one Spec for Map, like the bakemcp
+// command does with several inputs: every spec keeps its own base URL. Names
+// must be unique, also once turned into environment variable names (users-api
+// and users_api both become USERS_API).
+func Merge(specs []NamedSpec) (*Spec, error) {
+	named := make([]openapi.Named, 0, len(specs))
+	for _, s := range specs {
+		named = append(named, openapi.Named{Name: s.Name, Prefix: s.Prefix, Result: s.Spec})
+	}
+	return openapi.Merge(named)
+}
+
+// MapOptions control Map.
+type MapOptions struct {
+	Resources  bool      // Expose read-only GET operations as MCP resources instead of tools
+	Prompts    bool      // Generate MCP prompts from tags and response links
+	Projection bool      // Add the fields argument to every tool with a JSON response (x-mcp-projection decides per operation)
+	Confirm    bool      // Ask the user to confirm each request of a destructive tool (x-mcp-confirm decides per operation)
+	Elicit     bool      // Ask the user for required parameters a call leaves out (x-mcp-elicit decides per operation)
+	Workflows  io.Reader // Arazzo document whose workflows become composite tools (optional)
+}
+
+// Map turns the operations of spec into an MCP server named after spec.Info;
+// set the Name, Version and Instructions of the result to override them.
+// Problems with the document's x-ratelimit extension are added to spec.Warnings.
+func Map(spec *Spec, opts MapOptions) (*Server, error) {
+	mopts := mapping.ServerOptions{
+		BuildOptions: mapping.BuildOptions{Projection: opts.Projection, Confirm: opts.Confirm, Elicit: opts.Elicit},
+		Resources:    opts.Resources,
+		Prompts:      opts.Prompts,
+	}
+	if opts.Workflows != nil {
+		doc, err := arazzo.Parse(opts.Workflows)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Arazzo workflows: %w", err)
+		}
+		mopts.Workflows = doc
+	}
+	return mapping.MapServer(spec, mopts)
+}
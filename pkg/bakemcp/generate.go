@@ -0,0 +1,83 @@
+package bakemcp
+
+import (
+	"fmt"
+	"strings"
+
+	"bakemcp/internal/generator/node"
+)
+
+// FS is where Generate reads and writes the project, in the style of
+// io/fs.ReadFileFS plus the write operations generation needs.
+type FS = node.FS
+
+// OsFS is the local filesystem; RecordingFS keeps written files in memory.
+type (
+	OsFS        = node.OsFS
+	RecordingFS = node.RecordingFS
+	File        = node.File
+)
+
+// Files of a generated project, relative to the output directory.
+const (
+	PackageFile  = node.PackageFile
+	EntryFile    = node.EntryFile
+	ToolsFile    = node.ToolsFile
+	ManifestFile = node.ManifestFile
+	ReportFile   = node.ReportFile
+)
+
+// GenerateOptions control Generate.
+type GenerateOptions struct {
+	FS                FS        // Defaults to OsFS
+	TemplatesDir      string    // Templates replacing the built-in ones of the same relative path (optional)
+	Warnings          []Warning // Warnings of earlier stages (e.g. Spec.Warnings) to include in the report
+	Report            bool      // Also write the report to ReportFile
+	OverwriteModified bool      // Replace files even when they were modified since the last run
+	Clean             bool      // Replace or delete every generated file of the previous generation, even modified ones; edited user-owned files are kept
+}
+
+// ConflictError is returned by Generate when generated files were modified
+// since the last run, or exist without having been written by it; nothing is
+// written.
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("generated files were modified since the last run or not written by bakemcp: %s", strings.Join(e.Files, ", "))
+}
+
+// Render returns the files of the Node project for srv without writing them.
+func Render(srv *Server) ([]File, error) {
+	return node.Render(srv)
+}
+
+// Generate writes the Node project for srv to outDir and returns its report.
+// Like the bakemcp command it keeps edited user-owned files, removes files
+// that are no longer generated and records what it wrote in ManifestFile.
+func Generate(outDir string, srv *Server, opts GenerateOptions) (*Report, error) {
+	if opts.FS == nil {
+		opts.FS = OsFS{}
+	}
+	tmpl, err := node.LoadTemplates(opts.TemplatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid templates: %w", err)
+	}
+	gen, err := node.Prepare(outDir, srv, node.PrepareOptions{
+		Templates: tmpl,
+		Warnings:  opts.Warnings,
+		Report:    opts.Report,
+		Plan:      node.PlanOptions{OverwriteModified: opts.OverwriteModified, Clean: opts.Clean, FS: opts.FS},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := node.Conflicts(gen.Plan); len(conflicts) > 0 {
+		return nil, &ConflictError{Files: conflicts}
+	}
+	if err := node.Apply(outDir, gen.Plan, gen.Manifest, opts.FS); err != nil {
+		return nil, err
+	}
+	return gen.Report, nil
+}
@@ -0,0 +1,10 @@
+// Package templates embeds the templates bakemcp generates projects from.
+package templates
+
+import "embed"
+
+// NodeFastMCP holds the templates of the Node project built on fastmcp,
+// under the node-fastmcp directory (see internal/generator/node).
+//
+//go:embed node-fastmcp
+var NodeFastMCP embed.FS
@@ -13,7 +13,8 @@ import (
 )
 
 // Contract: after generating with fixture OpenAPI, package.json exists and is valid JSON;
-// required fields (name, scripts.start, dependency fastmcp); entry script exists.
+// required fields (name, scripts.start, dependency fastmcp); entry script, generated
+// tools module and manifest exist.
 func TestGeneratedProject_Contract(t *testing.T) {
 	specPath := filepath.Join("..", "fixtures", "openapi3-minimal.json")
 	data, err := os.ReadFile(specPath)
@@ -66,4 +67,13 @@ func TestGeneratedProject_Contract(t *testing.T) {
 	if _, err := os.Stat(entryPath); err != nil {
 		t.Fatalf("entry script index.js missing: %v", err)
 	}
+
+	// generated tools module and manifest exist
+	if _, err := os.Stat(filepath.Join(dir, "generated", "tools.js")); err != nil {
+		t.Fatalf("generated/tools.js missing: %v", err)
+	}
+	manifest, err := node.LoadManifest(dir)
+	if err != nil || manifest == nil || len(manifest.Files) != 3 {
+		t.Fatalf("manifest should record the 3 project files: %+v (%v)", manifest, err)
+	}
 }
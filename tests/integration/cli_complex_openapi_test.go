@@ -77,13 +77,8 @@ func TestCLI_ComplexOpenAPI_FullPipeline(t *testing.T) {
 		}
 	}
 
-	// ─── Phase 3: Validate index.js content ─────────────────────────────
-	entryPath := filepath.Join(outDir, "index.js")
-	entryBytes, err := os.ReadFile(entryPath)
-	if err != nil {
-		t.Fatalf("cannot read index.js: %v", err)
-	}
-	entryContent := string(entryBytes)
+	// ─── Phase 3: Validate index.js and generated/tools.js content ──────
+	entryContent := readProject(t, outDir)
 
 	// All 20 expected tool names derived from operationIds in the complex fixture
 	expectedTools := []string{
@@ -168,9 +163,11 @@ func TestCLI_ComplexOpenAPI_FullPipeline(t *testing.T) {
 		`method: "PATCH"`:  2,
 		`method: "DELETE"`: 1,
 	}
-	// Count HTTP method occurrences in fetch calls
+	// Count HTTP method occurrences in the fetch calls of the tools; the
+	// runtimes before register (e.g. pagination) send requests of their own
+	registrations := entryContent[strings.Index(entryContent, "export function register"):]
 	for method, expectedCount := range expectedMethods {
-		count := strings.Count(entryContent, method)
+		count := strings.Count(registrations, method)
 		if count != expectedCount {
 			t.Errorf("expected %d occurrences of %s, got %d", expectedCount, method, count)
 		}
@@ -282,11 +279,7 @@ func TestCLI_ComplexOpenAPI_ParseAndMapOperations(t *testing.T) {
 		t.Fatalf("cli.Run failed: %v (exit %d)", err, code)
 	}
 
-	entryBytes, err := os.ReadFile(filepath.Join(outDir, "index.js"))
-	if err != nil {
-		t.Fatalf("cannot read index.js: %v", err)
-	}
-	entryContent := string(entryBytes)
+	entryContent := readProject(t, outDir)
 
 	// ─── Sub-test: operations with query-heavy endpoints ────────────────
 	t.Run("ListProducts_has_many_query_filters", func(t *testing.T) {
@@ -422,3 +415,17 @@ func TestCLI_ComplexOpenAPI_ParseAndMapOperations(t *testing.T) {
 type parseResult struct {
 	OperationCount int
 }
+
+// readProject returns the entry script followed by the generated tools module.
+func readProject(t *testing.T, dir string) string {
+	t.Helper()
+	var b strings.Builder
+	for _, name := range []string{"index.js", filepath.Join("generated", "tools.js")} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("cannot read %s: %v", name, err)
+		}
+		b.Write(data)
+	}
+	return b.String()
+}
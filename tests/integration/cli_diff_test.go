@@ -0,0 +1,56 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/cli"
+)
+
+// Integration: diff classifies tool changes between two spec versions.
+func TestCLI_Diff(t *testing.T) {
+	oldPath := filepath.Join("..", "fixtures", "openapi3-diff-old.yaml")
+	newPath := filepath.Join("..", "fixtures", "openapi3-diff-new.yaml")
+
+	var text bytes.Buffer
+	code, err := cli.Diff(cli.DiffConfig{OldPath: oldPath, NewPath: newPath, Out: &text})
+	if err != nil || code != 0 {
+		t.Fatalf("cli.Diff: %v (exit %d)", err, code)
+	}
+	for _, want := range []string{
+		"BREAKING fetch_order: tool get_order was renamed to fetch_order",
+		"BREAKING delete_order: tool delete_order was removed",
+		"BREAKING create_order: new required argument body.customerId",
+		"BREAKING list_orders: argument status no longer accepts cancelled",
+		"5 breaking, 3 non-breaking changes",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("output should contain %q:\n%s", want, text.String())
+		}
+	}
+
+	var js bytes.Buffer
+	code, err = cli.Diff(cli.DiffConfig{OldPath: oldPath, NewPath: newPath, Format: "json", FailOnBreaking: true, Out: &js})
+	if err != nil || code != 1 {
+		t.Fatalf("cli.Diff -fail-on-breaking: expected exit 1, got %d (%v)", code, err)
+	}
+	var out struct {
+		Breaking    int
+		NonBreaking int
+		Changes     []struct{ Kind, Tool string }
+	}
+	if err := json.Unmarshal(js.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, js.String())
+	}
+	if out.Breaking != 5 || out.NonBreaking != 3 || len(out.Changes) != 8 {
+		t.Errorf("unexpected JSON report: %+v", out)
+	}
+
+	code, err = cli.Diff(cli.DiffConfig{OldPath: oldPath, NewPath: oldPath, FailOnBreaking: true, Out: &bytes.Buffer{}})
+	if err != nil || code != 0 {
+		t.Errorf("identical specs: expected exit 0, got %d (%v)", code, err)
+	}
+}
@@ -1,9 +1,11 @@
 package integration_test
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,3 +64,368 @@ func TestCLI_GenerateThenNpmInstallAndStart(t *testing.T) {
 	}
 	// Test passes if we reached here: install succeeded and start launched
 }
+
+// Integration: an overlay edits the spec before parsing.
+func TestCLI_Overlay(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		InputPath:   filepath.Join("..", "fixtures", "openapi3-complex.json"),
+		OverlayPath: filepath.Join("..", "fixtures", "overlay.yaml"),
+		OutputDir:   outDir,
+	}
+	code, err := cli.Run(cfg)
+	if err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("cannot read tools.js: %v", err)
+	}
+	content := string(data)
+	if strings.Contains(content, "register_webhook") {
+		t.Error("overlay should remove register_webhook")
+	}
+	if !strings.Contains(content, "Search the product catalog") {
+		t.Error("overlay should update the list_products summary")
+	}
+
+	cfg.InputPath = filepath.Join("..", "fixtures", "openapi3-minimal.json")
+	cfg.OutputDir = t.TempDir()
+	if code, err := cli.Run(cfg); err == nil || code != 2 {
+		t.Errorf("overlay extending another document: expected exit 2, got %d (%v)", code, err)
+	}
+
+	// extends is resolved against the overlay's directory, not matched by file name.
+	copied := filepath.Join(t.TempDir(), "openapi3-complex.json")
+	data, err = os.ReadFile(filepath.Join("..", "fixtures", "openapi3-complex.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(copied, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.InputPath = copied
+	cfg.OutputDir = t.TempDir()
+	if code, err := cli.Run(cfg); err == nil || code != 2 {
+		t.Errorf("overlay extending a file of the same name elsewhere: expected exit 2, got %d (%v)", code, err)
+	}
+
+	// With several inputs, only the extended one is patched.
+	cfg.InputPath = ""
+	cfg.Inputs = []cli.Input{
+		{Path: filepath.Join("..", "fixtures", "openapi3-complex.json")},
+		{Path: filepath.Join("..", "fixtures", "openapi3-minimal.json")},
+	}
+	cfg.OutputDir = t.TempDir()
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run with several inputs: %v (exit %d)", err, code)
+	}
+	if data, err = os.ReadFile(filepath.Join(cfg.OutputDir, "generated", "tools.js")); err != nil || !strings.Contains(string(data), "Search the product catalog") {
+		t.Errorf("overlay should patch the input it extends (%v)", err)
+	}
+}
+
+// Integration: an overlay without extends is ambiguous with several inputs.
+func TestCLI_OverlayWithoutExtends(t *testing.T) {
+	overlayPath := filepath.Join(t.TempDir(), "overlay.yaml")
+	ov := "overlay: 1.0.0\ninfo: {title: fixes, version: 1.0.0}\nactions:\n  - target: $.info\n    update: {description: patched}\n"
+	if err := os.WriteFile(overlayPath, []byte(ov), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := cli.Config{
+		Inputs: []cli.Input{
+			{Path: filepath.Join("..", "fixtures", "openapi3-complex.json")},
+			{Path: filepath.Join("..", "fixtures", "openapi3-minimal.json")},
+		},
+		OverlayPath: overlayPath,
+		OutputDir:   t.TempDir(),
+	}
+	code, err := cli.Run(cfg)
+	if err == nil || code != 2 || !strings.Contains(err.Error(), "extends") {
+		t.Errorf("expected exit 2 asking for extends, got %d (%v)", code, err)
+	}
+
+	cfg.Inputs = cfg.Inputs[:1]
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Errorf("a single input needs no extends: %v (exit %d)", err, code)
+	}
+}
+
+// Integration: the coverage report lists what the server cannot do.
+func TestCLI_Report(t *testing.T) {
+	outDir := t.TempDir()
+	var log strings.Builder
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-lint.yaml"),
+		OutputDir: outDir,
+		Report:    true,
+		Log:       &log,
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	if !strings.Contains(log.String(), "Generated 3 tools") || !strings.Contains(log.String(), "3 warnings") {
+		t.Errorf("summary: got\n%s", log.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, cli.ReportFile))
+	if err != nil {
+		t.Fatalf("cannot read report: %v", err)
+	}
+	var report struct {
+		Tools    int
+		Warnings []struct{ Stage, Operation, Pointer string }
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	stages := make(map[string]bool)
+	for _, w := range report.Warnings {
+		stages[w.Stage] = true
+	}
+	if report.Tools != 3 || !stages["parse"] || !stages["mapping"] || !stages["generate"] {
+		t.Errorf("report: got %+v", report)
+	}
+}
+
+// Integration: invalid extensions of an operation exposed as a resource are
+// reported like those of a tool.
+func TestCLI_ReportResourceWarnings(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "api.yaml")
+	doc := `openapi: 3.0.3
+info: {title: Files, version: "1"}
+paths:
+  /files:
+    get:
+      operationId: listFiles
+      x-mcp-timeout: soon
+      responses: {"200": {description: OK}}
+`
+	if err := os.WriteFile(spec, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var log strings.Builder
+	cfg := cli.Config{InputPath: spec, OutputDir: filepath.Join(dir, "out"), Resources: true, Report: true, Log: &log}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	if !strings.Contains(log.String(), "1 resources") || !strings.Contains(log.String(), "x-mcp-timeout must be") {
+		t.Errorf("the resource's x-mcp-timeout should be reported, got\n%s", log.String())
+	}
+}
+
+// Integration: regenerating keeps a hand-edited index.js and refuses to
+// overwrite a hand-edited generated/tools.js.
+func TestCLI_RegeneratePreservesUserEdits(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-minimal.json"),
+		OutputDir: outDir,
+		Log:       &strings.Builder{},
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	entry := filepath.Join(outDir, "index.js")
+	if err := os.WriteFile(entry, []byte("// my hooks\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A generated project can be regenerated without -f; index.js is kept.
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("regenerate: %v (exit %d)", err, code)
+	}
+	if data, _ := os.ReadFile(entry); string(data) != "// my hooks\n" {
+		t.Errorf("index.js should be kept, got %q", data)
+	}
+
+	tools := filepath.Join(outDir, "generated", "tools.js")
+	if err := os.WriteFile(tools, []byte("// patched\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code, err := cli.Run(cfg); err == nil || code != 3 {
+		t.Fatalf("modified tools.js: expected exit 3, got %d (%v)", code, err)
+	}
+
+	cfg.OverwriteModified = true
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("overwrite: %v (exit %d)", err, code)
+	}
+	if data, _ := os.ReadFile(entry); strings.Contains(string(data), "my hooks") {
+		t.Error("-overwrite-modified should replace index.js")
+	}
+}
+
+// Integration: files of a previous generation that are no longer produced are
+// removed; files bakemcp did not create are left alone.
+func TestCLI_RegenerateRemovesOrphans(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-minimal.json"),
+		OutputDir: outDir,
+		Report:    true,
+		Log:       &strings.Builder{},
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	notes := filepath.Join(outDir, "NOTES.md")
+	if err := os.WriteFile(notes, []byte("mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Report = false
+	var log strings.Builder
+	cfg.Log = &log
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("regenerate: %v (exit %d)", err, code)
+	}
+	if strings.Contains(log.String(), "Report written") {
+		t.Errorf("a run without -report should not claim to write it:\n%s", log.String())
+	}
+	if _, err := os.Stat(filepath.Join(outDir, cli.ReportFile)); err != nil {
+		t.Error("the report is optional: a run without -report should leave it alone")
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Error("files bakemcp did not create must be kept")
+	}
+
+	// -clean also replaces edited generated files, but not an edited index.js.
+	entry := filepath.Join(outDir, "index.js")
+	if err := os.WriteFile(entry, []byte("// my hooks\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tools := filepath.Join(outDir, "generated", "tools.js")
+	if err := os.WriteFile(tools, []byte("// patched\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Clean = true
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("clean: %v (exit %d)", err, code)
+	}
+	if data, _ := os.ReadFile(entry); !strings.Contains(string(data), "my hooks") {
+		t.Error("-clean should keep the edited index.js")
+	}
+	if data, _ := os.ReadFile(tools); strings.Contains(string(data), "patched") {
+		t.Error("-clean should replace generated/tools.js")
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Error("-clean must keep files bakemcp did not create")
+	}
+}
+
+// Integration: a directory generated before manifests existed has an index.js
+// bakemcp did not record. With -f it is replaced, so the server runs the new
+// generated/tools.js instead of the old tools.
+func TestCLI_RegenerateUnrecordedDirectory(t *testing.T) {
+	outDir := t.TempDir()
+	entry := filepath.Join(outDir, "index.js")
+	if err := os.WriteFile(entry, []byte("// old monolithic server\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-minimal.json"),
+		OutputDir: outDir,
+		Log:       &strings.Builder{},
+	}
+	if code, err := cli.Run(cfg); err == nil || code != 3 {
+		t.Fatalf("non-empty directory without -f: expected exit 3, got %d (%v)", code, err)
+	}
+	cfg.Force = true
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run with -f: %v (exit %d)", err, code)
+	}
+	if data, _ := os.ReadFile(entry); strings.Contains(string(data), "old monolithic server") {
+		t.Error("-f should replace an index.js bakemcp did not record")
+	}
+}
+
+// Integration: -templates replaces built-in templates of the same path.
+func TestCLI_Templates(t *testing.T) {
+	tmplDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmplDir, "partials"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tool := `// {{.Method}} {{.Path}}
+server.addTool(withOverrides({ name: {{quote .Name}}, parameters: {{.Schema}}, execute: async () => "stub" }));
+`
+	if err := os.WriteFile(filepath.Join(tmplDir, "partials", "tool.js.tmpl"), []byte(tool), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		InputPath:    filepath.Join("..", "fixtures", "openapi3-minimal.json"),
+		OutputDir:    outDir,
+		TemplatesDir: tmplDir,
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("cannot read tools.js: %v", err)
+	}
+	if !strings.Contains(string(data), `execute: async () => "stub"`) || !strings.Contains(string(data), "export function register") {
+		t.Errorf("tools.js should use the overridden tool partial inside the built-in tools.js:\n%s", data)
+	}
+
+	cfg.OutputDir = t.TempDir()
+	cfg.TemplatesDir = filepath.Join(tmplDir, "missing")
+	if code, err := cli.Run(cfg); err == nil || code != 2 {
+		t.Errorf("missing templates directory: expected exit 2, got %d (%v)", code, err)
+	}
+}
+
+func TestCLI_ServerIdentity(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-complex.json"),
+		OutputDir: outDir,
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "package.json"))
+	if err != nil {
+		t.Fatalf("cannot read package.json: %v", err)
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		t.Fatalf("package.json: %v", err)
+	}
+	if pkg.Name != "e-commerce-platform-api" || pkg.Version != "2.4.1" {
+		t.Errorf("package.json should be named after the spec info, got %s@%s", pkg.Name, pkg.Version)
+	}
+	tools, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("cannot read tools.js: %v", err)
+	}
+	if !strings.Contains(string(tools), `name: "e-commerce-platform-api"`) || !strings.Contains(string(tools), "instructions: ") {
+		t.Errorf("tools.js should export the server name and instructions:\n%.400s", tools)
+	}
+
+	cfg.OutputDir = t.TempDir()
+	cfg.ServerName = "Shop Orders"
+	cfg.ServerVersion = "v3"
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run with overrides: %v (exit %d)", err, code)
+	}
+	data, _ = os.ReadFile(filepath.Join(cfg.OutputDir, "package.json"))
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		t.Fatalf("package.json: %v", err)
+	}
+	if pkg.Name != "shop-orders" || pkg.Version != "3.0.0" {
+		t.Errorf("overrides should win over the spec info, got %s@%s", pkg.Name, pkg.Version)
+	}
+
+	cfg.OutputDir = t.TempDir()
+	cfg.ServerVersion = "banana"
+	if code, err := cli.Run(cfg); err == nil || code != 1 {
+		t.Errorf("invalid server version: expected exit 1, got %d (%v)", code, err)
+	}
+}
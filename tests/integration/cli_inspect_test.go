@@ -0,0 +1,145 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/cli"
+)
+
+// Integration: inspect prints the tool list without writing files.
+func TestCLI_Inspect(t *testing.T) {
+	inputs := []cli.Input{{Path: filepath.Join("..", "fixtures", "openapi3-complex.json")}}
+
+	var table bytes.Buffer
+	if code, err := cli.Inspect(cli.InspectConfig{Inputs: inputs, Out: &table}); err != nil || code != 0 {
+		t.Fatalf("cli.Inspect: %v (exit %d)", err, code)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 21 || !strings.HasPrefix(lines[0], "NAME") {
+		t.Fatalf("expected a header and 20 rows, got\n%s", table.String())
+	}
+	if !strings.Contains(table.String(), "customerId*:string") || !strings.Contains(table.String(), "read-only, idempotent") {
+		t.Errorf("table should show arguments and hints:\n%s", table.String())
+	}
+
+	var js bytes.Buffer
+	if code, err := cli.Inspect(cli.InspectConfig{Inputs: inputs, Format: "json", Out: &js}); err != nil || code != 0 {
+		t.Fatalf("cli.Inspect json: %v (exit %d)", err, code)
+	}
+	var out struct {
+		Tools []struct {
+			Name        string
+			Method      string
+			InputSchema map[string]interface{}
+			Annotations map[string]bool
+		}
+	}
+	if err := json.Unmarshal(js.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(out.Tools) != 20 || out.Tools[0].InputSchema == nil || out.Tools[0].Annotations == nil {
+		t.Errorf("json tools: got %+v", out.Tools)
+	}
+}
+
+// Integration: inspect shows the arguments the generated tools take: body
+// properties next to the parameters, and the arguments the mapping flags add.
+func TestCLI_InspectArguments(t *testing.T) {
+	inputs := []cli.Input{{Path: filepath.Join("..", "fixtures", "openapi3-complex.json")}}
+	var table bytes.Buffer
+	cfg := cli.InspectConfig{Inputs: inputs, Confirm: true, Projection: true, Out: &table}
+	if code, err := cli.Inspect(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Inspect: %v (exit %d)", err, code)
+	}
+	rows := make(map[string]string)
+	for _, line := range strings.Split(table.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows[fields[0]] = line
+		}
+	}
+	for tool, want := range map[string]string{
+		"update_order_status":   "internalNote:string, orderId*:string, status*:string, tracking:object",
+		"delete_product":        "confirm:boolean, productId*:string",
+		"list_products":         "fetchAll:boolean, fields:string,",
+		"create_product_review": "body*:string, cons:array,",
+	} {
+		if !strings.Contains(rows[tool], want) {
+			t.Errorf("%s: arguments should contain %q, got\n%s", tool, want, rows[tool])
+		}
+	}
+
+	table.Reset()
+	cfg.Resources = true
+	if code, err := cli.Inspect(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Inspect -resources: %v (exit %d)", err, code)
+	}
+	if strings.Contains(table.String(), "get_customer ") {
+		t.Errorf("operations exposed as resources should not be listed as tools:\n%s", table.String())
+	}
+}
+
+// Integration: a dry run lists the files it would write and writes none.
+func TestCLI_DryRun(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "out")
+	var out, log bytes.Buffer
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-minimal.json"),
+		OutputDir: outDir,
+		DryRun:    true,
+		Out:       &out,
+		Log:       &log,
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Error("dry run should not create the output directory")
+	}
+	if !strings.Contains(out.String(), "create    "+filepath.Join(outDir, "index.js")) {
+		t.Errorf("dry run output: got\n%s", out.String())
+	}
+
+	cfg.DryRun = false
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	cfg.DryRun, cfg.Force = true, true
+	out.Reset()
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	if !strings.Contains(out.String(), "unchanged "+filepath.Join(outDir, "index.js")) {
+		t.Errorf("rerun dry run output: got\n%s", out.String())
+	}
+}
+
+// Integration: regenerating an unchanged spec changes nothing, so a dry run
+// on a freshly generated directory only reports unchanged files.
+func TestCLI_DryRunStable(t *testing.T) {
+	outDir := t.TempDir()
+	var out bytes.Buffer
+	cfg := cli.Config{
+		InputPath: filepath.Join("..", "fixtures", "openapi3-complex.json"),
+		OutputDir: outDir,
+		Out:       &out,
+		Log:       &bytes.Buffer{},
+	}
+	if code, err := cli.Run(cfg); err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	cfg.DryRun = true
+	for i := 0; i < 3; i++ {
+		out.Reset()
+		if code, err := cli.Run(cfg); err != nil || code != 0 {
+			t.Fatalf("cli.Run -dry-run: %v (exit %d)", err, code)
+		}
+		if !strings.Contains(out.String(), "unchanged "+filepath.Join(outDir, "generated", "tools.js")) {
+			t.Fatalf("dry run on an unchanged spec should report tools.js unchanged, got\n%s", out.String())
+		}
+	}
+}
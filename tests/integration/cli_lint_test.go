@@ -0,0 +1,35 @@
+package integration_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"bakemcp/internal/cli"
+)
+
+// Integration: lint exits non-zero at the configured severity.
+func TestCLI_Lint_FailOn(t *testing.T) {
+	spec := filepath.Join("..", "fixtures", "openapi3-lint.yaml")
+	cases := []struct {
+		spec   string
+		failOn string
+		want   int
+	}{
+		{spec, "error", 0},
+		{spec, "warning", 1},
+		{spec, "none", 0},
+		{filepath.Join("..", "fixtures", "openapi3-minimal.json"), "info", 0},
+		{filepath.Join("..", "fixtures", "openapi3-split", "api.yaml"), "info", 0},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		code, err := cli.Lint(cli.LintConfig{InputPath: c.spec, FailOn: c.failOn, Out: &out})
+		if err != nil {
+			t.Fatalf("cli.Lint(%s, %s): %v", c.spec, c.failOn, err)
+		}
+		if code != c.want {
+			t.Errorf("cli.Lint(%s, fail-on %s): exit %d, want %d\n%s", c.spec, c.failOn, code, c.want, out.String())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/cli"
+)
+
+// Integration: several specs (one prefixed) merge into a single server with
+// one base URL env var per source.
+func TestCLI_MultipleSpecs(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := cli.Config{
+		Inputs: []cli.Input{
+			cli.ParseInput("shop=" + filepath.Join("..", "fixtures", "openapi3-complex.json")),
+			cli.ParseInput(filepath.Join("..", "fixtures", "openapi.yaml")),
+		},
+		OutputDir: outDir,
+	}
+	code, err := cli.Run(cfg)
+	if err != nil || code != 0 {
+		t.Fatalf("cli.Run: %v (exit %d)", err, code)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("cannot read tools.js: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"process.env.SHOP_BASE_URL",
+		"process.env.OPENAPI_BASE_URL",
+		`name: "shop_list_products"`,
+		`name: "get_ping"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("tools.js missing %q", want)
+		}
+	}
+}
+
+func TestCLI_MultipleSpecs_DuplicateNames(t *testing.T) {
+	fixture := filepath.Join("..", "fixtures", "openapi.yaml")
+	cfg := cli.Config{
+		Inputs:    []cli.Input{{Path: fixture}, {Path: fixture}},
+		OutputDir: t.TempDir(),
+	}
+	code, err := cli.Run(cfg)
+	if err == nil || code != 2 {
+		t.Fatalf("expected exit 2 for inputs sharing a name, got %d (%v)", code, err)
+	}
+}
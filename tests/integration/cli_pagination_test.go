@@ -0,0 +1,59 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/cli"
+)
+
+const paginatedSpec = `openapi: 3.0.3
+info: {title: Paged, version: 1.0.0}
+servers: [{url: "https://api.example.com"}]
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      parameters:
+        - {name: page, in: query, schema: {type: integer}}
+        - {name: per_page, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: object, properties: {items: {type: array, items: {type: object}}}}
+`
+
+// The pagination runtime is only part of tools.js when a tool is paginated.
+func TestCLI_PaginatedSpec(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(paginatedSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		input string
+		paged bool
+	}{
+		{specPath, true},
+		{filepath.Join("..", "fixtures", "openapi3-minimal.json"), false},
+	} {
+		outDir := t.TempDir()
+		if code, err := cli.Run(cli.Config{InputPath: tc.input, OutputDir: outDir}); err != nil || code != 0 {
+			t.Fatalf("cli.Run %s: %v (exit %d)", tc.input, err, code)
+		}
+		data, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+		if err != nil {
+			t.Fatalf("cannot read tools.js: %v", err)
+		}
+		js := string(data)
+		if got := strings.Contains(js, "async function fetchPages("); got != tc.paged {
+			t.Errorf("%s: pagination runtime included = %v, want %v", tc.input, got, tc.paged)
+		}
+		if tc.paged && !strings.Contains(js, "await fetchPages(\"list_orders\"") {
+			t.Errorf("%s: list_orders should fetch pages:\n%s", tc.input, js)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bakemcp/internal/cli"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of Watch.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor waits until log contains substr n times.
+func waitFor(t *testing.T, log *syncBuffer, substr string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(log.String(), substr) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q (x%d) in log:\n%s", substr, n, log.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Integration: watch regenerates on change, reports tool changes and survives parse errors.
+func TestCLI_Watch(t *testing.T) {
+	fixture := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("..", "fixtures", name))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		return data
+	}
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "api.yaml")
+	outDir := filepath.Join(dir, "out")
+	if err := os.WriteFile(spec, fixture("openapi3-diff-old.yaml"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() {
+		code, _ := cli.Watch(ctx, cli.Config{
+			InputPath:    spec,
+			OutputDir:    outDir,
+			PollInterval: 10 * time.Millisecond,
+			Debounce:     30 * time.Millisecond,
+			Log:          log,
+		})
+		done <- code
+	}()
+	waitFor(t, log, "Generated 4 tools", 1)
+
+	if err := os.WriteFile(spec, []byte("openapi: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, log, "Error: invalid OpenAPI", 1)
+
+	if err := os.WriteFile(spec, fixture("openapi3-diff-new.yaml"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, log, "Tool changes:", 1)
+	for _, want := range []string{
+		"BREAKING fetch_order: tool get_order was renamed to fetch_order",
+		"5 breaking, 3 non-breaking changes",
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("log should contain %q:\n%s", want, log.String())
+		}
+	}
+	tools, err := os.ReadFile(filepath.Join(outDir, "generated", "tools.js"))
+	if err != nil || !strings.Contains(string(tools), `name: "list_customers"`) {
+		t.Errorf("tools.js should be regenerated from the new spec (err %v)", err)
+	}
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != 0 {
+			t.Errorf("Watch: expected exit 0 after cancel, got %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
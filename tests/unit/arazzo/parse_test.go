@@ -0,0 +1,51 @@
+package arazzo_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/arazzo"
+)
+
+func TestParse_Fixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("..", "..", "fixtures", "arazzo-orders.yaml"))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+	doc, err := arazzo.Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Workflows) != 1 {
+		t.Fatalf("expected 1 workflow, got %d", len(doc.Workflows))
+	}
+	wf := doc.Workflows[0]
+	if wf.WorkflowID != "placeAndFetchOrder" || len(wf.Steps) != 2 {
+		t.Fatalf("workflow: got %q with %d steps", wf.WorkflowID, len(wf.Steps))
+	}
+	if wf.Steps[0].OperationID != "createOrder" {
+		t.Errorf("qualified operationId should be reduced, got %q", wf.Steps[0].OperationID)
+	}
+	if wf.Steps[0].Outputs["orderId"] != "$response.body#/id" {
+		t.Errorf("step outputs: got %v", wf.Steps[0].Outputs)
+	}
+	if wf.Steps[1].Parameters[0].Value != "$steps.placeOrder.outputs.orderId" {
+		t.Errorf("step parameter value: got %v", wf.Steps[1].Parameters[0].Value)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]string{
+		"version":     "arazzo: 2.0.0\nworkflows: [{workflowId: a, steps: [{stepId: s, operationId: op}]}]",
+		"noWorkflows": "arazzo: 1.0.0\nworkflows: []",
+		"noStepOp":    "arazzo: 1.0.0\nworkflows: [{workflowId: a, steps: [{stepId: s, operationPath: x}]}]",
+	}
+	for name, doc := range cases {
+		if _, err := arazzo.Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bakemcp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/pkg/bakemcp"
+)
+
+func fixture(name string) string {
+	return filepath.Join("..", "..", "fixtures", name)
+}
+
+func TestParseMapGenerate(t *testing.T) {
+	ov, err := os.Open(fixture("overlay.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ov.Close()
+	spec, err := bakemcp.ParseFile(fixture("openapi3-complex.json"), bakemcp.ParseOptions{Overlay: ov})
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	srv, err := bakemcp.Map(spec, bakemcp.MapOptions{Resources: true})
+	if err != nil {
+		t.Fatalf("Map: %v", err)
+	}
+	if len(srv.Resources) == 0 {
+		t.Error("Resources option should map GET operations to resources")
+	}
+	for _, tool := range srv.Tools {
+		if tool.Name == "register_webhook" {
+			t.Error("overlay should remove register_webhook")
+		}
+	}
+
+	outDir := t.TempDir()
+	report, err := bakemcp.Generate(outDir, srv, bakemcp.GenerateOptions{Warnings: spec.Warnings, Report: true})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if report.Tools != len(srv.Tools) || report.Resources != len(srv.Resources) {
+		t.Errorf("report counts: got %+v", report)
+	}
+	for _, name := range []string{bakemcp.PackageFile, bakemcp.EntryFile, bakemcp.ToolsFile, bakemcp.ManifestFile, bakemcp.ReportFile} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+}
+
+func TestGenerate_RecordingFSKeepsEdits(t *testing.T) {
+	spec, err := bakemcp.ParseFile(fixture("openapi3-minimal.json"), bakemcp.ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	srv, err := bakemcp.Map(spec, bakemcp.MapOptions{})
+	if err != nil {
+		t.Fatalf("Map: %v", err)
+	}
+
+	fs := &bakemcp.RecordingFS{}
+	if _, err := bakemcp.Generate("out", srv, bakemcp.GenerateOptions{FS: fs}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	entry := filepath.Join("out", bakemcp.EntryFile)
+	tools := filepath.Join("out", bakemcp.ToolsFile)
+	_ = fs.WriteFile(entry, []byte("// my hooks\n"), 0755)
+
+	// An edited entry point is kept; an edited tools.js is a conflict.
+	if _, err := bakemcp.Generate("out", srv, bakemcp.GenerateOptions{FS: fs}); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	if data, _ := fs.ReadFile(entry); string(data) != "// my hooks\n" {
+		t.Errorf("edited %s should be kept, got %q", bakemcp.EntryFile, data)
+	}
+	_ = fs.WriteFile(tools, []byte("patched"), 0644)
+	_, err = bakemcp.Generate("out", srv, bakemcp.GenerateOptions{FS: fs})
+	var conflict *bakemcp.ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != bakemcp.ToolsFile {
+		t.Fatalf("expected a ConflictError for %s, got %v", bakemcp.ToolsFile, err)
+	}
+	if _, err := bakemcp.Generate("out", srv, bakemcp.GenerateOptions{FS: fs, OverwriteModified: true}); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if data, _ := fs.ReadFile(tools); !strings.Contains(string(data), "export function register") {
+		t.Errorf("tools.js should be overwritten, got %q", data)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var specs []bakemcp.NamedSpec
+	for _, in := range []struct{ name, prefix, file string }{
+		{"shop", "", "openapi3-complex.json"},
+		{"mini", "mini", "openapi3-minimal.json"},
+	} {
+		spec, err := bakemcp.ParseFile(fixture(in.file), bakemcp.ParseOptions{})
+		if err != nil {
+			t.Fatalf("ParseFile %s: %v", in.file, err)
+		}
+		specs = append(specs, bakemcp.NamedSpec{Name: in.name, Prefix: in.prefix, Spec: spec})
+	}
+	merged, err := bakemcp.Merge(specs)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	srv, err := bakemcp.Map(merged, bakemcp.MapOptions{})
+	if err != nil {
+		t.Fatalf("Map: %v", err)
+	}
+	sources := make(map[string]bool)
+	for _, tool := range srv.Tools {
+		sources[tool.Source] = true
+		if tool.Source == "mini" && !strings.HasPrefix(tool.Name, "mini_") {
+			t.Errorf("%s: tools of a prefixed spec should be prefixed", tool.Name)
+		}
+	}
+	if !sources["shop"] || !sources["mini"] {
+		t.Errorf("tools should keep their source, got %v", sources)
+	}
+
+	if _, err := bakemcp.Merge([]bakemcp.NamedSpec{specs[0], specs[0]}); err == nil {
+		t.Error("Merge should reject specs sharing a name")
+	}
+	collide := []bakemcp.NamedSpec{
+		{Name: "users-api", Spec: specs[0].Spec},
+		{Name: "users_api", Spec: specs[1].Spec},
+	}
+	if _, err := bakemcp.Merge(collide); err == nil || !strings.Contains(err.Error(), "USERS_API_BASE_URL") {
+		t.Errorf("Merge should reject names sharing an environment variable, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package diff_test
+
+import (
+	"testing"
+
+	"bakemcp/internal/diff"
+	"bakemcp/internal/domain/model"
+)
+
+func tool(name, method, path string, schema map[string]interface{}) *model.MCPTool {
+	return &model.MCPTool{Name: name, Method: method, Path: path, InputSchema: schema}
+}
+
+func object(required []string, props map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": props, "required": required}
+}
+
+func byKind(changes []diff.Change) map[string]diff.Change {
+	out := make(map[string]diff.Change)
+	for _, c := range changes {
+		out[c.Kind+" "+c.Tool+" "+c.Argument] = c
+	}
+	return out
+}
+
+func TestCompare_Tools(t *testing.T) {
+	prev := []*model.MCPTool{
+		tool("get_order", "GET", "/orders/{id}", object(nil, nil)),
+		tool("delete_order", "DELETE", "/orders/{id}", object(nil, nil)),
+	}
+	next := []*model.MCPTool{
+		tool("fetch_order", "GET", "/orders/{id}", object(nil, nil)),
+		tool("list_orders", "GET", "/orders", object(nil, nil)),
+	}
+	got := byKind(diff.Compare(prev, next))
+	if len(got) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", got)
+	}
+	if c, ok := got["tool-renamed fetch_order "]; !ok || !c.Breaking {
+		t.Errorf("get_order -> fetch_order should be a breaking rename: %+v", got)
+	}
+	if c, ok := got["tool-removed delete_order "]; !ok || !c.Breaking {
+		t.Errorf("delete_order should be a breaking removal: %+v", got)
+	}
+	if c, ok := got["tool-added list_orders "]; !ok || c.Breaking {
+		t.Errorf("list_orders should be a non-breaking addition: %+v", got)
+	}
+}
+
+func TestCompare_Arguments(t *testing.T) {
+	prev := []*model.MCPTool{tool("create_order", "POST", "/orders", object([]string{"body"}, map[string]interface{}{
+		"status": map[string]interface{}{"type": "string", "enum": []interface{}{"open", "closed"}},
+		"limit":  map[string]interface{}{"type": "integer"},
+		"note":   map[string]interface{}{"type": "string"},
+		"body": object([]string{"sku"}, map[string]interface{}{
+			"sku":  map[string]interface{}{"type": "string"},
+			"tags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		}),
+	}))}
+	next := []*model.MCPTool{tool("create_order", "POST", "/orders", object([]string{"body", "note"}, map[string]interface{}{
+		"status": map[string]interface{}{"type": "string", "enum": []interface{}{"open", "pending"}},
+		"limit":  map[string]interface{}{"type": "number"},
+		"note":   map[string]interface{}{"type": "string"},
+		"body": object([]string{"sku"}, map[string]interface{}{
+			"sku":  map[string]interface{}{"type": "string"},
+			"tags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+			"gift": map[string]interface{}{"type": "boolean"},
+		}),
+	}))}
+
+	changes := diff.Compare(prev, next)
+	got := byKind(changes)
+	want := map[string]bool{
+		"argument-required create_order note":   true,
+		"enum-narrowed create_order status":     true,
+		"enum-widened create_order status":      false,
+		"type-changed create_order limit":       false,
+		"type-changed create_order body.tags[]": true,
+		"argument-added create_order body.gift": false,
+	}
+	for key, breaking := range want {
+		c, ok := got[key]
+		if !ok {
+			t.Errorf("missing change %q in %+v", key, changes)
+			continue
+		}
+		if c.Breaking != breaking {
+			t.Errorf("%s: breaking = %v, want %v", key, c.Breaking, breaking)
+		}
+	}
+	if len(changes) != len(want) {
+		t.Errorf("expected %d changes, got %+v", len(want), changes)
+	}
+	if !diff.HasBreaking(changes) {
+		t.Error("HasBreaking should be true")
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	tools := []*model.MCPTool{tool("get_order", "GET", "/orders/{id}", object([]string{"id"}, map[string]interface{}{
+		"id": map[string]interface{}{"type": "string"},
+	}))}
+	if changes := diff.Compare(tools, tools); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
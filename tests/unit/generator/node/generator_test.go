@@ -63,7 +63,511 @@ func TestGenerate_ProducesEntryScript(t *testing.T) {
 	if !strings.Contains(content, "FastMCP") {
 		t.Error("entry script should reference FastMCP")
 	}
-	if !strings.Contains(content, "ping") {
-		t.Error("entry script should register tool ping")
+	if !strings.Contains(content, `import { register, serverInfo } from "./generated/tools.js"`) {
+		t.Error("entry script should import the generated tools module")
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("ReadFile tools.js: %v", err)
+	}
+	if !strings.Contains(string(data), "ping") {
+		t.Error("tools module should register tool ping")
+	}
+}
+
+func TestGenerateServer_RegistersResources(t *testing.T) {
+	dir := t.TempDir()
+	srv := &model.MCPServer{
+		Resources: []*model.MCPResource{
+			{Name: "list_products", Description: "List products", URI: "api://products", MimeType: "application/json", Path: "/products"},
+			{Name: "get_product", Description: "Get product", URI: "api://products/{productId}", MimeType: "application/json", Path: "/products/{productId}",
+				Params: []model.MCPToolParam{{Name: "productId", In: "path", Required: true}}},
+		},
+	}
+	if err := node.GenerateServer(dir, srv, nil); err != nil {
+		t.Fatalf("GenerateServer: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("ReadFile tools.js: %v", err)
+	}
+	content := string(data)
+	if !strings.Contains(content, `server.addResource({`) || !strings.Contains(content, `uri: "api://products"`) {
+		t.Error("tools module should register api://products with server.addResource")
+	}
+	if !strings.Contains(content, `server.addResourceTemplate({`) || !strings.Contains(content, `uriTemplate: "api://products/{productId}"`) {
+		t.Error("tools module should register api://products/{productId} with server.addResourceTemplate")
+	}
+	if !strings.Contains(content, "encodeURIComponent(args.productId)") {
+		t.Error("resource template should interpolate its path argument")
+	}
+	for _, want := range []string{
+		`if (!res.ok) throw new Error(errorResult(res, body, {}).content[0].text);`,
+		"return { text: resourceText(fitResponse(body, 0)) };",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("resources should report errors and fit their contents: missing %q", want)
+		}
+	}
+}
+
+func TestGenerateServer_RegistersPrompts(t *testing.T) {
+	dir := t.TempDir()
+	srv := &model.MCPServer{
+		Prompts: []*model.MCPPrompt{{Name: "orders_tools", Description: "Overview", Text: "Use list_orders\nthen get_order"}},
+	}
+	if err := node.GenerateServer(dir, srv, nil); err != nil {
+		t.Fatalf("GenerateServer: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("ReadFile tools.js: %v", err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "server.addPrompt({") || !strings.Contains(content, `name: "orders_tools"`) {
+		t.Error("tools module should register prompt orders_tools with server.addPrompt")
+	}
+	if !strings.Contains(content, `"Use list_orders\nthen get_order"`) {
+		t.Error("prompt text should be emitted as an escaped string literal")
+	}
+}
+
+func TestGenerateServer_RegistersWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	srv := &model.MCPServer{
+		Workflows: []*model.MCPWorkflow{{
+			Name:        "place_order",
+			Description: "Place and fetch",
+			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"sku": map[string]interface{}{"type": "string"}}},
+			Steps: []model.MCPWorkflowStep{{
+				ID:      "create",
+				Tool:    &model.MCPTool{Method: "POST", Path: "/orders"},
+				Body:    map[string]interface{}{"sku": "$inputs.sku"},
+				Outputs: map[string]string{"id": "$response.body#/id"},
+			}},
+			Outputs: map[string]string{"id": "$steps.create.outputs.id"},
+		}},
+	}
+	if err := node.GenerateServer(dir, srv, nil); err != nil {
+		t.Fatalf("GenerateServer: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("ReadFile tools.js: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"async function runWorkflow(",
+		`name: "place_order"`,
+		`stepId: "create"`,
+		`body: {"sku":"$inputs.sku"}`,
+		`outputs: {"id":"$steps.create.outputs.id"}`,
+		"isError: true };",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("tools module missing %q", want)
+		}
+	}
+}
+
+func TestGenerate_BaseURLPerSource(t *testing.T) {
+	dir := t.TempDir()
+	tools := []*model.MCPTool{
+		{Name: "list_users", Method: "GET", Path: "/users", BaseURL: "http://users", Source: "users"},
+		{Name: "list_invoices", Method: "GET", Path: "/invoices", BaseURL: "http://billing", Source: "billing-api"},
+		{Name: "list_reports", Method: "GET", Path: "/reports", BaseURL: "http://reports", Source: "2024-api"},
+	}
+	if err := node.Generate(dir, tools, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "generated", "tools.js"))
+	if err != nil {
+		t.Fatalf("ReadFile tools.js: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		`const USERS_BASE_URL = process.env.USERS_BASE_URL || "http://users";`,
+		`const BILLING_API_BASE_URL = process.env.BILLING_API_BASE_URL || "http://billing";`,
+		`send("list_users", USERS_BASE_URL + "/users"`,
+		`send("list_invoices", BILLING_API_BASE_URL + "/invoices"`,
+		`const _2024_API_BASE_URL = process.env._2024_API_BASE_URL || "http://reports";`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("tools module missing %q", want)
+		}
+	}
+	if strings.Contains(content, "const BASE_URL") {
+		t.Error("merged server should not declare an unnamed BASE_URL")
+	}
+}
+
+func TestGenerate_BaseURLCollision(t *testing.T) {
+	tools := []*model.MCPTool{
+		{Name: "list_users", Method: "GET", Path: "/users", BaseURL: "http://users", Source: "users-api"},
+		{Name: "list_people", Method: "GET", Path: "/people", BaseURL: "http://people", Source: "users_api"},
+	}
+	err := node.Generate(t.TempDir(), tools, nil)
+	if err == nil || !strings.Contains(err.Error(), "USERS_API_BASE_URL") {
+		t.Fatalf("Generate should reject sources sharing a base URL constant, got %v", err)
+	}
+}
+
+func TestCheck_ReportsUnsupportedParamsAndSchemas(t *testing.T) {
+	srv := &model.MCPServer{Tools: []*model.MCPTool{{
+		Name: "search", Method: "POST", Path: "/search",
+		Params: []model.MCPToolParam{
+			{Name: "q", In: "query", Schema: map[string]interface{}{"type": "string"}},
+			{Name: "X-Trace", In: "header", Schema: map[string]interface{}{"type": "string"}, Pointer: "/parameters/2"},
+		},
+		Body: &model.MCPToolBody{Schema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"filter": map[string]interface{}{"oneOf": []interface{}{}},
+				"tags":   map[string]interface{}{"type": "array"},
+				"limit":  map[string]interface{}{"type": "integer"},
+			},
+		}},
+	}}}
+	warnings := node.Check(srv)
+
+	got := make(map[string]string)
+	for _, w := range warnings {
+		got[w.Pointer] = w.Message
+		if w.Stage != "generate" || w.Operation != "POST /search" {
+			t.Errorf("unexpected warning %+v", w)
+		}
+	}
+	body := "/paths/~1search/post/requestBody/content/application~1json/schema/properties/"
+	for _, ptr := range []string{
+		"/paths/~1search/post/parameters/2",
+		body + "filter",
+		body + "tags",
+	} {
+		if _, ok := got[ptr]; !ok {
+			t.Errorf("expected a warning at %s, got %v", ptr, got)
+		}
+	}
+	if len(warnings) != 3 {
+		t.Errorf("expected 3 warnings, got %+v", warnings)
+	}
+}
+
+func TestGenerate_EmitsAnnotations(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "list_items", Method: "GET", Path: "/items",
+		Annotations: &model.MCPToolAnnotations{ReadOnlyHint: true, IdempotentHint: true, OpenWorldHint: true},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(fs.Files) != 4 || fs.Files[2].Name != filepath.Join("out", "generated", "tools.js") {
+		t.Fatalf("recorded files: got %+v", fs.Files)
+	}
+	want := "annotations: { readOnlyHint: true, destructiveHint: false, idempotentHint: true, openWorldHint: true },"
+	if !strings.Contains(string(fs.Files[2].Data), want) {
+		t.Errorf("tools.js should contain %q", want)
+	}
+	if _, err := os.Stat("out"); !os.IsNotExist(err) {
+		t.Error("RecordingFS should not touch the filesystem")
+	}
+}
+
+func TestGenerate_PassesRequestOptions(t *testing.T) {
+	fs := &node.RecordingFS{}
+	retries := 0
+	tools := []*model.MCPTool{
+		{Name: "list_items", Method: "GET", Path: "/items"},
+		{Name: "export_items", Method: "POST", Path: "/exports", HTTP: model.MCPToolHTTP{TimeoutMs: 120000, Retries: &retries}},
+	}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		`timeoutMs: envInt("HTTP_TIMEOUT_MS", 30000),`,
+		`send("list_items", BASE_URL + "/items", { method: "GET" });`,
+		`send("export_items", BASE_URL + "/exports", { method: "POST" }, {"timeoutMs":120000,"retries":0});`,
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerateServer_EmitsRateLimits(t *testing.T) {
+	fs := &node.RecordingFS{}
+	srv := &model.MCPServer{
+		RateLimit: &model.MCPRateLimit{Limit: 20, WindowMs: 1000, Concurrency: 4},
+		Tools: []*model.MCPTool{
+			{Name: "list_items", Method: "GET", Path: "/items", HTTP: model.MCPToolHTTP{RateLimit: &model.MCPRateLimit{Limit: 2, WindowMs: 60000}}},
+			{Name: "get_item", Method: "GET", Path: "/items/{id}"},
+		},
+	}
+	if err := node.GenerateServer("out", srv, fs); err != nil {
+		t.Fatalf("GenerateServer: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		`limit: envInt("RATE_LIMIT", 20),`,
+		`concurrency: envInt("MAX_CONCURRENCY", 4),`,
+		`"GET /items": {"limit":2,"windowMs":60000},`,
+		`send("list_items", BASE_URL + "/items", { method: "GET" }, {"limitKey":"GET /items"});`,
+		"if (attempt > 0) await retryToken(opts.limitKey);",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_ReturnsErrorResults(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{
+		{Name: "get_order", Method: "GET", Path: "/orders", Errors: map[string]string{"404": "No such order", "default": "Unexpected error"}},
+		{Name: "list_items", Method: "GET", Path: "/items"},
+	}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		`execute: reportFailures(async () => {`,
+		`if (!res.ok) return errorResult(res, body, {"404":"No such order","default":"Unexpected error"});`,
+		`if (!res.ok) return errorResult(res, body, {});`,
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+	if strings.Contains(js, `throw new Error("HTTP "`) {
+		t.Error("tools should not throw on HTTP errors")
+	}
+}
+
+func TestGenerate_FitsResponses(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{
+		{Name: "list_items", Method: "GET", Path: "/items", HTTP: model.MCPToolHTTP{MaxResponse: 20000}, Params: []model.MCPToolParam{
+			{Name: "status", In: "query"}, {Name: "page", In: "query"}, {Name: "limit", In: "query"},
+		}},
+		{Name: "get_item", Method: "GET", Path: "/items/{id}", Params: []model.MCPToolParam{{Name: "id", In: "path", Required: true}}},
+	}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		`const MAX_RESPONSE_CHARS = envInt("MAX_RESPONSE_CHARS", 100000);`,
+		`return fitResponse(body, 20000, "Use the status, page or limit arguments to get a smaller response.");`,
+		`return fitResponse(body, 0, "Narrow the request to get a smaller response.");`,
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_ProjectsFields(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "list_items", Method: "GET", Path: "/items", Fields: []string{"id", "name"},
+		Params: []model.MCPToolParam{{Name: "page", In: "query", Schema: map[string]interface{}{"type": "integer"}}},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"fields: z.string().optional(),",
+		"const { fields, ...apiArgs } = args;",
+		"return fitResponse(project(body, fields), 0,",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_FetchesPages(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "list_items", Method: "GET", Path: "/items",
+		Params:     []model.MCPToolParam{{Name: "page", In: "query", Schema: map[string]interface{}{"type": "integer"}}},
+		Pagination: &model.MCPPagination{Style: "page", Param: "page", Start: 1, Items: "data"},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"fetchAll: z.boolean().optional(),",
+		"maxPages: z.number().int().min(1).optional(),",
+		"const { fetchAll, maxPages, ...apiArgs } = args;",
+		`const pages = await fetchPages("list_items", url, res, body, {"style":"page","param":"page","start":1,"items":"data"}, { fetchAll, maxPages }, 0);`,
+		"return fitResponse(pages.body, 0,",
+		"pages.note);",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_PollsAsyncOperations(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{
+		{Name: "create_export", Method: "POST", Path: "/exports", Async: &model.MCPAsync{IntervalMs: 5000}},
+		{Name: "list_exports", Method: "GET", Path: "/exports"},
+	}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"execute: reportFailures(async (args, context) => {",
+		`const res = await sendAndPoll(context, {"intervalMs":5000}, "create_export", BASE_URL + "/exports", { method: "POST" });`,
+		`const res = await send("list_exports", BASE_URL + "/exports", { method: "GET" });`,
+		"async function sendAndPoll(",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_OmitsUnusedRuntimes(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{Name: "list_exports", Method: "GET", Path: "/exports"}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, unwanted := range []string{
+		"function project(",
+		"async function fetchPages(",
+		"async function sendAndPoll(",
+		"async function readStream(",
+		"async function confirmRequest(",
+		"async function elicitMissing(",
+		"mcpServer = server;",
+	} {
+		if strings.Contains(js, unwanted) {
+			t.Errorf("tools.js should not contain %q when no tool uses it", unwanted)
+		}
+	}
+}
+
+func TestGenerate_ReadsStreams(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "watch_events", Method: "GET", Path: "/events", Stream: "sse",
+		Annotations: &model.MCPToolAnnotations{ReadOnlyHint: true, OpenWorldHint: true},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"openWorldHint: true, streamingHint: true },",
+		"execute: reportFailures(async (args, context) => {",
+		`const res = await send("watch_events", BASE_URL + "/events", { method: "GET" }, {"stream":true});`,
+		`if (!res.ok) return errorResult(res, await receive("watch_events", res), {});`,
+		`const events = await readStream("watch_events", res, "sse", context);`,
+		"async function readStream(",
+		"res.release?.();",
+		"return fitResponse(events.body, 0,",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+	if strings.Contains(js, "const body = await receive(") {
+		t.Errorf("a stream should not be read whole")
+	}
+}
+
+func TestGenerate_ConfirmsRequests(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "delete_order", Method: "DELETE", Path: "/orders/{id}", Confirm: true,
+		Params: []model.MCPToolParam{{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}}},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"confirm: z.boolean().optional(),",
+		"execute: reportFailures(async (args, context) => {",
+		"const { confirm, ...apiArgs } = args;",
+		`const init = { method: "DELETE" };`,
+		`const declined = await confirmRequest(context, "delete_order", url, init, confirm);`,
+		"if (declined) return declined;",
+		`const res = await send("delete_order", url, init);`,
+		"mcpServer = server;",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+// URL parameters are read off args, so names that are locals of execute or
+// are not identifiers still make valid JS.
+func TestGenerate_ReadsURLParamsFromArgs(t *testing.T) {
+	fs := &node.RecordingFS{}
+	str := map[string]interface{}{"type": "string"}
+	tools := []*model.MCPTool{{
+		Name: "update_order", Method: "PUT", Path: "/orders/{init}", Confirm: true,
+		Params: []model.MCPToolParam{
+			{Name: "init", In: "path", Required: true, Schema: str},
+			{Name: "dry-run", In: "query", Schema: str},
+		},
+		Body: &model.MCPToolBody{Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"declined": str}}},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		`"dry-run": z.string().optional(),`,
+		"const bodyArgs = { ...args };",
+		`for (const name of ["init", "dry-run"]) delete bodyArgs[name];`,
+		"let url = `${BASE_URL}/orders/${encodeURIComponent(args.init)}`;",
+		`if (args["dry-run"] !== undefined) qp.append("dry-run", String(args["dry-run"]));`,
+		"body: JSON.stringify(bodyArgs),",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_ElicitsMissingArguments(t *testing.T) {
+	fs := &node.RecordingFS{}
+	tools := []*model.MCPTool{{
+		Name: "get_order", Method: "GET", Path: "/orders/{id}", Elicit: []string{"id", "region"},
+		Params: []model.MCPToolParam{
+			{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "integer", "minimum": 1}},
+			{Name: "region", In: "query", Required: true, Schema: map[string]interface{}{"enum": []interface{}{"eu", "us"}}},
+		},
+	}}
+	if err := node.Generate("out", tools, fs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	js := string(fs.Files[2].Data)
+	for _, want := range []string{
+		"id: z.number().optional(),",
+		`region: z.enum(["eu", "us"]).optional(),`,
+		"execute: reportFailures(async (args, context) => {",
+		`const missing = await elicitMissing(context, "get_order", args, {"properties":{"id":{"minimum":1,"title":"id","type":"integer"},"region":{"enum":["eu","us"],"title":"region","type":"string"}},"required":["id","region"],"type":"object"});`,
+		"if (missing) return missing;",
+		"async function elicitMissing(context, tool, args, schema) {",
+		"mcpServer = server;",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("tools.js should contain %q", want)
+		}
 	}
 }
@@ -0,0 +1,161 @@
+package node_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bakemcp/internal/generator/node"
+)
+
+func TestPlan_DetectsModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []node.File{
+		{Name: "index.js", Data: []byte("entry v1"), Perm: 0644, UserOwned: true},
+		{Name: "generated/tools.js", Data: []byte("tools v1"), Perm: 0644},
+		{Name: "package.json", Data: []byte("{}"), Perm: 0644, UserOwned: true},
+	}
+	plan := node.Plan(dir, files, nil, node.PlanOptions{})
+	for _, p := range plan {
+		if p.Action != node.ActionCreate {
+			t.Errorf("%s: first run should create, got %s", p.Name, p.Action)
+		}
+	}
+	if err := node.Apply(dir, plan, nil, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	prev, err := node.LoadManifest(dir)
+	if err != nil || prev == nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+
+	// The user edits index.js and tools.js; bakemcp changes both and package.json stays.
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), []byte("my hooks"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "generated", "tools.js"), []byte("patched"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files[0].Data = []byte("entry v2")
+	files[1].Data = []byte("tools v2")
+
+	want := map[string]node.Action{"index.js": node.ActionKeep, "generated/tools.js": node.ActionConflict, "package.json": node.ActionUnchanged}
+	plan = node.Plan(dir, files, prev, node.PlanOptions{})
+	for _, p := range plan {
+		if p.Action != want[p.Name] {
+			t.Errorf("%s: got %s, want %s", p.Name, p.Action, want[p.Name])
+		}
+	}
+	if c := node.Conflicts(plan); len(c) != 1 || node.Apply(dir, plan, prev, nil) == nil {
+		t.Errorf("Apply should refuse conflicts %v", c)
+	}
+
+	plan = node.Plan(dir, files, prev, node.PlanOptions{OverwriteModified: true})
+	if err := node.Apply(dir, plan, prev, nil); err != nil {
+		t.Fatalf("Apply with overwrite: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "index.js"))
+	if string(data) != "entry v2" {
+		t.Errorf("overwrite should replace index.js, got %q", data)
+	}
+
+	// Unmodified files are updated on the next change.
+	prev, _ = node.LoadManifest(dir)
+	files[1].Data = []byte("tools v3")
+	if p := node.Plan(dir, files, prev, node.PlanOptions{})[1]; p.Action != node.ActionUpdate {
+		t.Errorf("unmodified tools.js: got %s, want update", p.Action)
+	}
+}
+
+func TestPlan_Orphans(t *testing.T) {
+	dir := t.TempDir()
+	files := []node.File{
+		{Name: "index.js", Data: []byte("entry"), Perm: 0644, UserOwned: true},
+		{Name: "generated/old.js", Data: []byte("old"), Perm: 0644},
+		{Name: "generated/stale.js", Data: []byte("stale"), Perm: 0644},
+	}
+	if err := node.Apply(dir, node.Plan(dir, files, nil, node.PlanOptions{}), nil, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "generated", "stale.js"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prev, _ := node.LoadManifest(dir)
+	prev.Files = append(prev.Files, node.ManifestEntry{Path: "../outside.txt", SHA256: "x"})
+
+	actions := func(plan []node.PlannedFile) map[string]node.Action {
+		out := make(map[string]node.Action)
+		for _, p := range plan {
+			out[p.Name] = p.Action
+		}
+		return out
+	}
+	got := actions(node.Plan(dir, files[:1], prev, node.PlanOptions{}))
+	want := map[string]node.Action{"index.js": node.ActionUnchanged, "generated/old.js": node.ActionDelete, "generated/stale.js": node.ActionDisown}
+	if len(got) != len(want) {
+		t.Fatalf("plan: got %v, want %v (untracked and outside files must not appear)", got, want)
+	}
+	for name, a := range want {
+		if got[name] != a {
+			t.Errorf("%s: got %s, want %s", name, got[name], a)
+		}
+	}
+	if got := actions(node.Plan(dir, files[:1], prev, node.PlanOptions{Clean: true})); got["generated/stale.js"] != node.ActionDelete {
+		t.Errorf("clean: modified orphan should be deleted, got %s", got["generated/stale.js"])
+	}
+
+	plan := node.Plan(dir, files[:1], prev, node.PlanOptions{Clean: true})
+	if err := node.Apply(dir, plan, prev, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "generated")); !os.IsNotExist(err) {
+		t.Error("empty generated/ directory should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("files bakemcp did not create must be left alone")
+	}
+}
+
+func TestPlan_UnrecordedAndOptionalFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []node.File{
+		{Name: "index.js", Data: []byte("entry"), Perm: 0644, UserOwned: true},
+		{Name: "generated/tools.js", Data: []byte("tools"), Perm: 0644},
+		{Name: "report.json", Data: []byte("{}"), Perm: 0644},
+	}
+	if err := node.Apply(dir, node.Plan(dir, files, nil, node.PlanOptions{}), nil, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	prev, _ := node.LoadManifest(dir)
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), []byte("my hooks"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files[0].Data = []byte("entry v2")
+
+	// Clean replaces generated files only; an optional file left out is retained.
+	plan := node.Plan(dir, files[:2], prev, node.PlanOptions{Clean: true, Optional: []string{"report.json"}})
+	want := map[string]node.Action{"index.js": node.ActionKeep, "generated/tools.js": node.ActionUnchanged, "report.json": node.ActionRetain}
+	for _, p := range plan {
+		if p.Action != want[p.Name] {
+			t.Errorf("%s: got %s, want %s", p.Name, p.Action, want[p.Name])
+		}
+	}
+	if err := node.Apply(dir, plan, prev, nil); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if m, _ := node.LoadManifest(dir); len(m.Files) != 3 {
+		t.Errorf("kept and retained files should stay in the manifest, got %+v", m.Files)
+	}
+
+	// Without a manifest entry a differing file is a conflict, even user-owned,
+	// unless Force replaces it.
+	if p := node.Plan(dir, files[:1], nil, node.PlanOptions{})[0]; p.Action != node.ActionConflict {
+		t.Errorf("unrecorded index.js: got %s, want conflict", p.Action)
+	}
+	if p := node.Plan(dir, files[:1], nil, node.PlanOptions{Force: true})[0]; p.Action != node.ActionOverwrite {
+		t.Errorf("unrecorded index.js with Force: got %s, want overwrite", p.Action)
+	}
+}
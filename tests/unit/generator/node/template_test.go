@@ -0,0 +1,81 @@
+package node_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/model"
+	"bakemcp/internal/generator/node"
+)
+
+func TestLoadTemplates_OverridesByPath(t *testing.T) {
+	dir := t.TempDir()
+	override := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Replace the prompt partial and add a partial it includes; other templates stay built in.
+	override("partials/prompt.js.tmpl", `{{include "partials/banner.js.tmpl" .}}server.addPrompt({ name: {{quote .Name}} });`+"\n")
+	override("partials/banner.js.tmpl", "// prompt {{.Name}}\n")
+	override("README.md", "not a template {{")
+
+	set, err := node.LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	srv := &model.MCPServer{
+		Tools:   []*model.MCPTool{{Name: "ping", Method: "GET", Path: "/ping"}},
+		Prompts: []*model.MCPPrompt{{Name: "guide", Text: "Use ping"}},
+	}
+	files, err := node.RenderWith(srv, set)
+	if err != nil {
+		t.Fatalf("RenderWith: %v", err)
+	}
+	tools := string(files[2].Data)
+	if !strings.Contains(tools, "  // prompt guide\n  server.addPrompt({ name: \"guide\" });\n}") {
+		t.Errorf("prompt partial should be overridden and indented:\n%s", tools)
+	}
+	if !strings.Contains(tools, `name: "ping",`) {
+		t.Errorf("tool partial should stay built in:\n%s", tools)
+	}
+	builtin, err := node.Render(srv)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if string(files[0].Data) != string(builtin[0].Data) {
+		t.Error("package.json should match the built-in template")
+	}
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	if _, err := node.LoadTemplates(filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("missing directory: expected a not-exist error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.js.tmpl"), []byte("{{if}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.LoadTemplates(dir); err == nil {
+		t.Error("expected a parse error")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "index.js.tmpl"), []byte("{{.Missing}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := node.LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	if _, err := node.RenderWith(&model.MCPServer{}, set); err == nil {
+		t.Error("expected an execution error for an unknown field")
+	}
+}
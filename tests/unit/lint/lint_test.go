@@ -0,0 +1,142 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/lint"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "fixtures", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func TestLint_FlagsMCPProblems(t *testing.T) {
+	findings := lint.Lint(readFixture(t, "openapi3-lint.yaml"), lint.Options{})
+
+	got := make(map[string]lint.Finding)
+	for _, f := range findings {
+		got[f.Rule] = f
+	}
+	for _, rule := range []string{
+		"body-unmappable",
+		"description-missing",
+		"operation-id-missing",
+		"parameter-unsupported",
+		"content-type-unsupported",
+		"tool-name-collision",
+	} {
+		if _, ok := got[rule]; !ok {
+			t.Errorf("expected a %s finding", rule)
+		}
+	}
+	if f := got["body-unmappable"]; f.Severity != lint.Warning || f.Pointer != "/paths/~1files/post/requestBody" {
+		t.Errorf("body-unmappable: got %+v", f)
+	}
+	for i := 1; i < len(findings); i++ {
+		if findings[i].Severity > findings[i-1].Severity {
+			t.Error("findings should be sorted by severity, most severe first")
+		}
+	}
+}
+
+func TestLint_InvalidExtension(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"x-ratelimit":{"limit":"lots"},"paths":{"/ping":{"get":{"operationId":"ping","summary":"Ping","x-mcp-timeout":"soon","responses":{"200":{"description":"ok"}}}}}}`
+	findings := lint.Lint([]byte(spec), lint.Options{})
+	if len(findings) != 2 || findings[0].Pointer != "/paths/~1ping/get/x-mcp-timeout" || findings[1].Pointer != "/x-ratelimit" {
+		t.Fatalf("expected two extension-invalid findings, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Rule != "extension-invalid" {
+			t.Errorf("rule: got %q", f.Rule)
+		}
+	}
+}
+
+func TestLint_CleanSpec(t *testing.T) {
+	findings := lint.Lint(readFixture(t, "openapi3-complex.json"), lint.Options{})
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+	findings = lint.Lint(readFixture(t, "openapi3-complex.json"), lint.Options{MaxSchemaBytes: 100})
+	if len(findings) == 0 || findings[0].Rule != "schema-too-large" {
+		t.Errorf("expected schema-too-large with a small limit, got %+v", findings)
+	}
+}
+
+func TestLint_ResolvesLocalRefs(t *testing.T) {
+	path := filepath.Join("..", "..", "fixtures", "openapi3-split", "api.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	if findings := lint.Lint(data, lint.Options{Path: path}); len(findings) != 0 {
+		t.Errorf("a spec split across local files should lint clean, got %+v", findings)
+	}
+}
+
+func TestLint_InvalidDocument(t *testing.T) {
+	findings := lint.Lint([]byte(`{"openapi":"3.0.3","info":{"title":"x"},"paths":{}}`), lint.Options{})
+	if len(findings) == 0 || findings[0].Rule != "openapi-invalid" {
+		t.Fatalf("expected openapi-invalid for missing info.version, got %+v", findings)
+	}
+	if !lint.Failed(findings, lint.Error) {
+		t.Error("Failed should report the error finding")
+	}
+}
+
+func TestWrite_Formats(t *testing.T) {
+	findings := lint.Lint(readFixture(t, "openapi3-lint.yaml"), lint.Options{})
+
+	var text bytes.Buffer
+	if err := lint.Write(&text, "text", findings, "spec.yaml"); err != nil {
+		t.Fatalf("text: %v", err)
+	}
+	if !strings.Contains(text.String(), "0 errors, 6 warnings, 0 info") {
+		t.Errorf("text summary: got\n%s", text.String())
+	}
+
+	var js bytes.Buffer
+	if err := lint.Write(&js, "json", findings, "spec.yaml"); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var report struct {
+		Findings []struct{ Rule, Severity string }
+	}
+	if err := json.Unmarshal(js.Bytes(), &report); err != nil || len(report.Findings) != len(findings) {
+		t.Fatalf("json report: %v (%d findings)", err, len(report.Findings))
+	}
+	if report.Findings[0].Severity != "warning" {
+		t.Errorf("json severity should be a name, got %q", report.Findings[0].Severity)
+	}
+
+	var sarif bytes.Buffer
+	if err := lint.Write(&sarif, "sarif", findings, "spec.yaml"); err != nil {
+		t.Fatalf("sarif: %v", err)
+	}
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct{ RuleID, Level string }
+		}
+	}
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil || log.Version != "2.1.0" {
+		t.Fatalf("sarif log: %v (version %q)", err, log.Version)
+	}
+	if len(log.Runs[0].Results) != len(findings) || log.Runs[0].Results[0].Level != "warning" {
+		t.Errorf("sarif results: got %+v", log.Runs[0].Results)
+	}
+
+	if err := lint.Write(&text, "xml", findings, "spec.yaml"); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
@@ -0,0 +1,56 @@
+package mapping_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestOperationToMCPTool_Async(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted bool
+		ext      interface{}
+		want     *model.MCPAsync
+		warning  string
+	}{
+		{name: "202 documented", accepted: true, want: &model.MCPAsync{}},
+		{name: "no 202", want: nil},
+		{name: "opted out", accepted: true, ext: false, want: nil},
+		{name: "opted in", ext: true, want: &model.MCPAsync{}},
+		{name: "configured", ext: map[string]interface{}{"statusUrl": "job.href", "interval": "5s", "timeout": 60000.0},
+			want: &model.MCPAsync{StatusURL: "job.href", IntervalMs: 5000, TimeoutMs: 60000}},
+		{name: "invalid interval", ext: map[string]interface{}{"interval": -1.0}, want: &model.MCPAsync{},
+			warning: "x-mcp-async interval must be a positive number of milliseconds"},
+		{name: "invalid value", accepted: true, ext: "yes", want: &model.MCPAsync{},
+			warning: "x-mcp-async must be a boolean or an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &model.Operation{Path: "/exports", Method: "POST", OperationID: "createExport", Accepted: tt.accepted}
+			if tt.ext != nil {
+				op.Extensions = map[string]interface{}{"x-mcp-async": tt.ext}
+			}
+			tool := mapping.OperationToMCPTool(op, "")
+			if !reflect.DeepEqual(tool.Async, tt.want) {
+				t.Errorf("async: got %+v, want %+v", tool.Async, tt.want)
+			}
+			if (tt.want != nil) != strings.Contains(tool.Description, "the tool waits until it finishes") {
+				t.Errorf("description: got %q", tool.Description)
+			}
+			if tt.warning == "" {
+				if len(tool.Warnings) != 0 {
+					t.Errorf("unexpected warnings %+v", tool.Warnings)
+				}
+				return
+			}
+			if len(tool.Warnings) != 1 || !strings.Contains(tool.Warnings[0].Message, tt.warning) ||
+				tool.Warnings[0].Pointer != "/paths/~1exports/post/x-mcp-async" {
+				t.Errorf("want one warning containing %q, got %+v", tt.warning, tool.Warnings)
+			}
+		})
+	}
+}
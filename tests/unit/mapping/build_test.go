@@ -0,0 +1,26 @@
+package mapping_test
+
+import (
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestBuild(t *testing.T) {
+	ops := []*model.Operation{
+		{Path: "/orders/{id}", Method: "DELETE", OperationID: "deleteOrder",
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}}}},
+		{Path: "/refunds", Method: "POST", OperationID: "refund", Extensions: map[string]interface{}{"x-mcp-confirm": true}},
+	}
+
+	tools := mapping.Build(ops, "https://api.example.com", mapping.BuildOptions{})
+	if tools[0].Confirm || tools[0].Elicit != nil || !tools[1].Confirm {
+		t.Errorf("the zero options should apply the extensions only, got confirm %v/%v, elicit %v", tools[0].Confirm, tools[1].Confirm, tools[0].Elicit)
+	}
+
+	tools = mapping.Build(ops, "https://api.example.com", mapping.BuildOptions{Confirm: true, Elicit: true})
+	if !tools[0].Confirm || len(tools[0].Elicit) != 1 || tools[0].BaseURL != "https://api.example.com" {
+		t.Errorf("the options should turn the passes on, got %+v", tools[0])
+	}
+}
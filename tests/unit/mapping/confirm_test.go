@@ -0,0 +1,59 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestApplyConfirmation(t *testing.T) {
+	ops := []*model.Operation{
+		{Path: "/orders/{id}", Method: "DELETE", OperationID: "deleteOrder"},
+		{Path: "/orders/{id}", Method: "PATCH", OperationID: "updateOrder"},
+		{Path: "/orders/{id}", Method: "PUT", OperationID: "replaceOrder", Extensions: map[string]interface{}{"x-mcp-confirm": false}},
+		{Path: "/orders", Method: "GET", OperationID: "listOrders"},
+		{Path: "/refunds", Method: "POST", OperationID: "refund", Extensions: map[string]interface{}{"x-mcp-confirm": true}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyConfirmation(tools, ops, true)
+
+	want := []bool{true, true, false, false, true}
+	for i, tool := range tools {
+		if tool.Confirm != want[i] {
+			t.Errorf("%s: Confirm = %v, want %v", tool.Name, tool.Confirm, want[i])
+		}
+		props, _ := tool.InputSchema["properties"].(map[string]interface{})
+		if (props["confirm"] != nil) != want[i] || strings.Contains(tool.Description, "asked to confirm") != want[i] {
+			t.Errorf("%s: the confirm argument and description should match Confirm, got %v", tool.Name, props)
+		}
+		if len(tool.Warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %+v", tool.Name, tool.Warnings)
+		}
+	}
+}
+
+func TestApplyConfirmation_PerOperation(t *testing.T) {
+	ops := []*model.Operation{
+		{Path: "/orders/{id}", Method: "DELETE", OperationID: "deleteOrder"},
+		{Path: "/refunds", Method: "POST", OperationID: "refund", Extensions: map[string]interface{}{"x-mcp-confirm": true},
+			RequestBody: &model.RequestBody{Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"confirm": map[string]interface{}{"type": "boolean"}}}}},
+		{Path: "/payouts", Method: "POST", OperationID: "payout", Extensions: map[string]interface{}{"x-mcp-confirm": "always"}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyConfirmation(tools, ops, false)
+
+	for _, tool := range tools {
+		if tool.Confirm {
+			t.Errorf("%s: should not confirm", tool.Name)
+		}
+	}
+	if len(tools[1].Warnings) != 1 || tools[1].Warnings[0].Pointer != "/paths/~1refunds/post/x-mcp-confirm" ||
+		!strings.Contains(tools[1].Warnings[0].Message, `request body already has a property named "confirm"`) {
+		t.Errorf("a clashing body property should be reported, got %+v", tools[1].Warnings)
+	}
+	if len(tools[2].Warnings) != 1 || !strings.Contains(tools[2].Warnings[0].Message, "x-mcp-confirm must be a boolean") {
+		t.Errorf("a non-boolean x-mcp-confirm should be reported, got %+v", tools[2].Warnings)
+	}
+}
@@ -0,0 +1,73 @@
+package mapping_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestApplyElicitation(t *testing.T) {
+	str := map[string]interface{}{"type": "string"}
+	ops := []*model.Operation{
+		{Path: "/orders/{id}", Method: "GET", OperationID: "getOrder", Parameters: []model.Parameter{
+			{Name: "id", In: "path", Required: true, Schema: str},
+			{Name: "region", In: "query", Required: true, Schema: map[string]interface{}{"enum": []interface{}{"eu", "us"}}},
+			{Name: "filter", In: "query", Required: true, Schema: map[string]interface{}{"type": "object"}},
+			{Name: "X-Tenant", In: "header", Required: true, Schema: str},
+			{Name: "limit", In: "query", Schema: map[string]interface{}{"type": "integer"}},
+		}},
+		{Path: "/users/{id}", Method: "GET", OperationID: "getUser", Extensions: map[string]interface{}{"x-mcp-elicit": false},
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true, Schema: str}}},
+		{Path: "/health", Method: "GET", OperationID: "health"},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyElicitation(tools, ops, true)
+
+	if want := []string{"id", "region"}; !reflect.DeepEqual(tools[0].Elicit, want) {
+		t.Errorf("Elicit = %v, want %v", tools[0].Elicit, want)
+	}
+	if want := []string{"filter", "X-Tenant"}; !reflect.DeepEqual(tools[0].InputSchema["required"], want) {
+		t.Errorf("required = %v, want %v", tools[0].InputSchema["required"], want)
+	}
+	if !strings.Contains(tools[0].Description, "If a call leaves out id or region, the user is asked for them.") {
+		t.Errorf("the description should name the elicited arguments, got %q", tools[0].Description)
+	}
+	if tools[1].Elicit != nil || tools[1].InputSchema["required"] == nil {
+		t.Errorf("x-mcp-elicit: false should opt out, got %v", tools[1].Elicit)
+	}
+	for _, tool := range tools {
+		if len(tool.Warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %+v", tool.Name, tool.Warnings)
+		}
+	}
+}
+
+func TestApplyElicitation_PerOperation(t *testing.T) {
+	ops := []*model.Operation{
+		{Path: "/orders/{id}", Method: "GET", OperationID: "getOrder", Extensions: map[string]interface{}{"x-mcp-elicit": true},
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "integer"}}}},
+		{Path: "/users/{id}", Method: "GET", OperationID: "getUser",
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}}}},
+		{Path: "/health", Method: "GET", OperationID: "health", Extensions: map[string]interface{}{"x-mcp-elicit": true}},
+		{Path: "/status", Method: "GET", OperationID: "status", Extensions: map[string]interface{}{"x-mcp-elicit": "yes"}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyElicitation(tools, ops, false)
+
+	if !reflect.DeepEqual(tools[0].Elicit, []string{"id"}) || tools[0].InputSchema["required"] != nil {
+		t.Errorf("x-mcp-elicit: true should elicit id, got %v (required %v)", tools[0].Elicit, tools[0].InputSchema["required"])
+	}
+	if tools[1].Elicit != nil {
+		t.Errorf("elicitation should be off by default, got %v", tools[1].Elicit)
+	}
+	if len(tools[2].Warnings) != 1 || tools[2].Warnings[0].Pointer != "/paths/~1health/get/x-mcp-elicit" ||
+		!strings.Contains(tools[2].Warnings[0].Message, "no required path or query parameter") {
+		t.Errorf("an operation with nothing to ask for should be reported, got %+v", tools[2].Warnings)
+	}
+	if len(tools[3].Warnings) != 1 || !strings.Contains(tools[3].Warnings[0].Message, "x-mcp-elicit must be a boolean") {
+		t.Errorf("a non-boolean x-mcp-elicit should be reported, got %+v", tools[3].Warnings)
+	}
+}
@@ -0,0 +1,61 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestPackageName(t *testing.T) {
+	for in, want := range map[string]string{
+		"E-Commerce Platform API": "e-commerce-platform-api",
+		"  Orders (v2) ":          "orders-v2",
+		"_internal.tools":         "internal.tools",
+		"@Acme/Orders API":        "@acme/orders-api",
+		"@/orders":                "orders",
+		"???":                     "generated-mcp",
+		"":                        "generated-mcp",
+	} {
+		if got := mapping.PackageName(in); got != want {
+			t.Errorf("PackageName(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := mapping.PackageName(strings.Repeat("a", 300)); len(got) != 214 {
+		t.Errorf("long names should be truncated to 214 characters, got %d", len(got))
+	}
+}
+
+func TestSemVer(t *testing.T) {
+	for in, want := range map[string]string{
+		"2.4.1":          "2.4.1",
+		"v2":             "2.0.0",
+		"1.2":            "1.2.0",
+		"01.2.3":         "1.2.3",
+		"1.0.0-beta.1":   "1.0.0-beta.1",
+		"3.1.0+build.5":  "3.1.0+build.5",
+		"2024-01-15":     "1.0.0",
+		"latest":         "1.0.0",
+		"":               "1.0.0",
+		"1.2.3.4":        "1.0.0",
+		"1.0.0-01":       "1.0.0",
+		"V10.20.30-rc.1": "10.20.30-rc.1",
+	} {
+		if got := mapping.SemVer(in); got != want {
+			t.Errorf("SemVer(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestApplyInfo(t *testing.T) {
+	srv := &model.MCPServer{}
+	mapping.ApplyInfo(srv, model.Info{Title: "Pet Store", Version: "v3", Description: "  Manage pets.\n"})
+	if srv.Name != "pet-store" || srv.Version != "3.0.0" || srv.Instructions != "Manage pets." {
+		t.Errorf("ApplyInfo: got %+v", srv)
+	}
+	mapping.ApplyInfo(srv, model.Info{})
+	if srv.Name != "generated-mcp" || srv.Version != "1.0.0" || srv.Instructions != "" {
+		t.Errorf("ApplyInfo without info: got %+v", srv)
+	}
+}
@@ -172,3 +172,143 @@ func TestOperationsToMCPTools_DeeplinkAPI_FullScenario(t *testing.T) {
 		}
 	}
 }
+
+// ── Multiple specs ───────────────────────────────────────────────────────
+
+func TestOperationsToMCPTools_MultipleSources(t *testing.T) {
+	users := &model.Source{Name: "users", BaseURL: "http://users"}
+	billing := &model.Source{Name: "billing", Prefix: "billing", BaseURL: "http://billing"}
+	catalog := &model.Source{Name: "catalog", BaseURL: "http://catalog"}
+	ops := []*model.Operation{
+		{OperationID: "listUsers", Path: "/users", Method: "GET", Source: users},
+		{OperationID: "listInvoices", Path: "/invoices", Method: "GET", Source: billing},
+		{OperationID: "health", Path: "/health", Method: "GET", Source: users},
+		{OperationID: "health", Path: "/health", Method: "GET", Source: catalog},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+
+	expected := []struct{ name, baseURL, source string }{
+		{"list_users", "http://users", "users"},
+		{"billing_list_invoices", "http://billing", "billing"},
+		{"users_get_health", "http://users", "users"},
+		{"catalog_get_health", "http://catalog", "catalog"},
+	}
+	for i, want := range expected {
+		if tools[i].Name != want.name || tools[i].BaseURL != want.baseURL || tools[i].Source != want.source {
+			t.Errorf("tools[%d] = {%q, %q, %q}, want %+v", i, tools[i].Name, tools[i].BaseURL, tools[i].Source, want)
+		}
+	}
+}
+
+func TestOperationsToMCPTools_Warnings(t *testing.T) {
+	ops := []*model.Operation{
+		{Path: "/reports", Method: "GET", OperationID: "list_1"},
+		{
+			Path: "/items/{id}", Method: "PUT", OperationID: "updateItem",
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true}},
+			RequestBody: &model.RequestBody{Schema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+			}},
+		},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+
+	if len(tools[0].Warnings) != 1 || tools[0].Warnings[0].Pointer != "/paths/~1reports/get/operationId" {
+		t.Errorf("renamed tool: got warnings %+v", tools[0].Warnings)
+	}
+	if len(tools[1].Warnings) != 1 || tools[1].Warnings[0].Pointer != "/paths/~1items~1{id}/put/requestBody/content/application~1json/schema/properties/id" {
+		t.Errorf("shadowed body property: got warnings %+v", tools[1].Warnings)
+	}
+	for _, tool := range tools {
+		for _, w := range tool.Warnings {
+			if w.Stage != "mapping" {
+				t.Errorf("stage: got %q", w.Stage)
+			}
+		}
+	}
+}
+
+func TestOperationToMCPTool_AnnotationsFromMethod(t *testing.T) {
+	cases := []struct {
+		method                            string
+		readOnly, destructive, idempotent bool
+	}{
+		{"GET", true, false, true},
+		{"POST", false, false, false},
+		{"PUT", false, true, true},
+		{"PATCH", false, true, false},
+		{"DELETE", false, true, true},
+	}
+	for _, c := range cases {
+		a := mapping.OperationToMCPTool(&model.Operation{Path: "/x", Method: c.method}, "").Annotations
+		if a == nil || a.ReadOnlyHint != c.readOnly || a.DestructiveHint != c.destructive || a.IdempotentHint != c.idempotent || !a.OpenWorldHint {
+			t.Errorf("%s: got %+v", c.method, a)
+		}
+	}
+}
+
+func TestOperationToMCPTool_HTTPExtensions(t *testing.T) {
+	tool := mapping.OperationToMCPTool(&model.Operation{Path: "/x", Method: "POST", Extensions: map[string]interface{}{
+		"x-mcp-timeout":      "2.5s",
+		"x-mcp-retries":      float64(0),
+		"x-mcp-idempotent":   true,
+		"x-mcp-max-response": float64(20000),
+	}}, "")
+	if tool.HTTP.TimeoutMs != 2500 || tool.HTTP.Retries == nil || *tool.HTTP.Retries != 0 || !tool.HTTP.Idempotent || tool.HTTP.MaxResponse != 20000 {
+		t.Errorf("HTTP: got %+v", tool.HTTP)
+	}
+	if len(tool.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %+v", tool.Warnings)
+	}
+
+	tool = mapping.OperationToMCPTool(&model.Operation{Path: "/x", Method: "GET", Extensions: map[string]interface{}{
+		"x-mcp-timeout":      float64(-1),
+		"x-mcp-retries":      1.5,
+		"x-mcp-idempotent":   "yes",
+		"x-mcp-max-response": float64(0),
+	}}, "")
+	if !tool.HTTP.IsZero() {
+		t.Errorf("invalid extensions should keep the defaults, got %+v", tool.HTTP)
+	}
+	if len(tool.Warnings) != 4 || tool.Warnings[0].Pointer != "/paths/~1x/get/x-mcp-timeout" {
+		t.Errorf("expected 4 warnings, got %+v", tool.Warnings)
+	}
+}
+
+func TestOperationToMCPTool_RateLimitExtension(t *testing.T) {
+	tool := mapping.OperationToMCPTool(&model.Operation{Path: "/x", Method: "GET", Extensions: map[string]interface{}{
+		"x-ratelimit": map[string]interface{}{"limit": float64(10), "window": "1m", "concurrency": float64(2)},
+	}}, "")
+	want := model.MCPRateLimit{Limit: 10, WindowMs: 60000, Concurrency: 2}
+	if tool.HTTP.RateLimit == nil || *tool.HTTP.RateLimit != want {
+		t.Errorf("RateLimit: got %+v, want %+v", tool.HTTP.RateLimit, want)
+	}
+
+	for _, v := range []interface{}{
+		"10/s",
+		map[string]interface{}{},
+		map[string]interface{}{"limit": float64(-1)},
+		map[string]interface{}{"burst": float64(5), "concurrency": float64(1)},
+		map[string]interface{}{"limit": float64(5), "per": "1s"},
+	} {
+		tool := mapping.OperationToMCPTool(&model.Operation{Path: "/x", Method: "GET", Extensions: map[string]interface{}{"x-ratelimit": v}}, "")
+		if tool.HTTP.RateLimit != nil || len(tool.Warnings) != 1 {
+			t.Errorf("%v: expected a warning and no limit, got %+v %+v", v, tool.HTTP.RateLimit, tool.Warnings)
+		}
+	}
+}
+
+func TestDocumentRateLimit(t *testing.T) {
+	rl, warnings := mapping.DocumentRateLimit(map[string]interface{}{"x-ratelimit": map[string]interface{}{"limit": float64(50)}})
+	if rl == nil || *rl != (model.MCPRateLimit{Limit: 50, WindowMs: 1000}) || len(warnings) != 0 {
+		t.Errorf("got %+v %+v", rl, warnings)
+	}
+	rl, warnings = mapping.DocumentRateLimit(map[string]interface{}{"x-ratelimit": true})
+	if rl != nil || len(warnings) != 1 || warnings[0].Pointer != "/x-ratelimit" {
+		t.Errorf("invalid limit: got %+v %+v", rl, warnings)
+	}
+	if rl, warnings := mapping.DocumentRateLimit(nil); rl != nil || warnings != nil {
+		t.Errorf("no extension: got %+v %+v", rl, warnings)
+	}
+}
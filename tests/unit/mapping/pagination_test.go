@@ -0,0 +1,123 @@
+package mapping_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func listOperation(id string, params []string, response map[string]interface{}) *model.Operation {
+	op := &model.Operation{Path: "/" + id, Method: "GET", OperationID: id, Response: response}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, model.Parameter{Name: name, In: "query", Schema: map[string]interface{}{"type": "string"}})
+	}
+	return op
+}
+
+func object(props map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+var itemList = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}}
+
+func TestOperationToMCPTool_DetectsPagination(t *testing.T) {
+	tests := []struct {
+		name string
+		op   *model.Operation
+		want *model.MCPPagination
+	}{
+		{"page and limit", listOperation("listA", []string{"page", "limit"}, object(map[string]interface{}{"data": itemList})),
+			&model.MCPPagination{Style: "page", Param: "page", SizeParam: "limit", Start: 1, Items: "data"}},
+		{"offset", listOperation("listB", []string{"offset", "limit"}, itemList),
+			&model.MCPPagination{Style: "offset", Param: "offset", SizeParam: "limit", Start: 1}},
+		{"nested cursor", listOperation("listC", []string{"cursor"}, object(map[string]interface{}{
+			"results": itemList,
+			"meta":    object(map[string]interface{}{"next_cursor": map[string]interface{}{"type": "string"}}),
+		})), &model.MCPPagination{Style: "cursor", Param: "cursor", Start: 1, Items: "results", NextCursor: "meta.next_cursor"}},
+		{"link header", func() *model.Operation {
+			op := listOperation("listD", nil, itemList)
+			op.Headers = []string{"Link"}
+			return op
+		}(), &model.MCPPagination{Style: "link", Start: 1}},
+		{"offset without a page size", listOperation("listE", []string{"offset"}, itemList), nil},
+		{"no item array", listOperation("listF", []string{"page"}, object(map[string]interface{}{"id": map[string]interface{}{"type": "string"}})), nil},
+		{"cursor without a next cursor", listOperation("listG", []string{"cursor"}, itemList), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := mapping.OperationToMCPTool(tt.op, "")
+			if !reflect.DeepEqual(tool.Pagination, tt.want) {
+				t.Errorf("pagination: got %+v, want %+v", tool.Pagination, tt.want)
+			}
+			props, _ := tool.InputSchema["properties"].(map[string]interface{})
+			if (props["fetchAll"] != nil) != (tt.want != nil) || (props["maxPages"] != nil) != (tt.want != nil) {
+				t.Errorf("fetchAll and maxPages should be arguments exactly when the tool is paginated, got %v", props)
+			}
+			if (tt.want != nil) != strings.Contains(tool.Description, "Results are paginated.") {
+				t.Errorf("description: got %q", tool.Description)
+			}
+			if len(tool.Warnings) != 0 {
+				t.Errorf("detection should not warn, got %+v", tool.Warnings)
+			}
+		})
+	}
+}
+
+func TestOperationToMCPTool_PaginationExtension(t *testing.T) {
+	withExt := func(op *model.Operation, ext interface{}) *model.Operation {
+		op.Extensions = map[string]interface{}{"x-mcp-pagination": ext}
+		return op
+	}
+	ops := []*model.Operation{
+		withExt(listOperation("listA", []string{"page", "limit"}, object(map[string]interface{}{"data": itemList})), false),
+		withExt(listOperation("listB", []string{"token"}, object(map[string]interface{}{"rows": itemList, "more": map[string]interface{}{"type": "string"}})),
+			map[string]interface{}{"style": "cursor", "param": "token", "nextCursor": "more"}),
+		withExt(listOperation("listC", []string{"page"}, object(map[string]interface{}{"data": itemList})), map[string]interface{}{"start": 0.0}),
+		withExt(listOperation("listD", []string{"page"}, itemList), map[string]interface{}{"style": "page", "param": "p"}),
+		withExt(listOperation("listE", nil, itemList), "yes"),
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+
+	if tools[0].Pagination != nil {
+		t.Errorf("x-mcp-pagination: false should turn detection off")
+	}
+	want := &model.MCPPagination{Style: "cursor", Param: "token", Start: 1, Items: "rows", NextCursor: "more"}
+	if !reflect.DeepEqual(tools[1].Pagination, want) {
+		t.Errorf("explicit cursor: got %+v, want %+v", tools[1].Pagination, want)
+	}
+	if p := tools[2].Pagination; p == nil || p.Style != "page" || p.Start != 0 {
+		t.Errorf("the extension should complete detection, got %+v", p)
+	}
+	for i, want := range map[int]string{3: `"p" is not a query parameter`, 4: "must be false or an object"} {
+		if tools[i].Pagination != nil || len(tools[i].Warnings) != 1 || !strings.Contains(tools[i].Warnings[0].Message, want) {
+			t.Errorf("%s: want one warning containing %q, got %+v", tools[i].Name, want, tools[i].Warnings)
+		}
+	}
+	if ptr := tools[4].Warnings[0].Pointer; ptr != "/paths/~1listE/get/x-mcp-pagination" {
+		t.Errorf("pointer: got %q", ptr)
+	}
+}
+
+func TestOperationToMCPTool_PaginationArgumentClash(t *testing.T) {
+	header := listOperation("listA", []string{"page"}, object(map[string]interface{}{"data": itemList}))
+	header.Parameters = append(header.Parameters, model.Parameter{Name: "fetchAll", In: "header", Schema: map[string]interface{}{"type": "boolean"}})
+	header.Extensions = map[string]interface{}{"x-mcp-pagination": map[string]interface{}{"style": "page"}}
+	body := listOperation("listB", []string{"page"}, object(map[string]interface{}{"data": itemList}))
+	body.RequestBody = &model.RequestBody{Schema: object(map[string]interface{}{"maxPages": map[string]interface{}{"type": "integer"}})}
+	body.Extensions = map[string]interface{}{"x-mcp-pagination": map[string]interface{}{"style": "page"}}
+	detected := listOperation("listC", []string{"page"}, object(map[string]interface{}{"data": itemList}))
+	detected.Parameters = append(detected.Parameters, model.Parameter{Name: "maxPages", In: "header", Schema: map[string]interface{}{"type": "integer"}})
+	tools := mapping.OperationsToMCPTools([]*model.Operation{header, body, detected}, "")
+
+	for i, want := range []string{`already has a header parameter named "fetchAll"`, `request body already has a property named "maxPages"`} {
+		if tools[i].Pagination != nil || len(tools[i].Warnings) != 1 || !strings.Contains(tools[i].Warnings[0].Message, want) {
+			t.Errorf("%s: want one warning containing %q, got %+v", tools[i].Name, want, tools[i].Warnings)
+		}
+	}
+	if tools[2].Pagination != nil || len(tools[2].Warnings) != 0 {
+		t.Errorf("detection should skip an operation with a maxPages parameter silently, got %+v, %+v", tools[2].Pagination, tools[2].Warnings)
+	}
+}
@@ -0,0 +1,85 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func productsOperation(id string, ext map[string]interface{}) *model.Operation {
+	return &model.Operation{
+		Path: "/products", Method: "GET", OperationID: id, Summary: "List products",
+		Extensions: ext,
+		Response: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"total": map[string]interface{}{"type": "integer"},
+				"data": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"id":  map[string]interface{}{"type": "string"},
+							"sku": map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyProjection(t *testing.T) {
+	ops := []*model.Operation{
+		productsOperation("listProducts", nil),
+		productsOperation("searchProducts", map[string]interface{}{"x-mcp-projection": false}),
+		{Path: "/ping", Method: "GET", OperationID: "ping"},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyProjection(tools, ops, true)
+
+	want := []string{"data", "data[].id", "data[].sku", "total"}
+	if strings.Join(tools[0].Fields, " ") != strings.Join(want, " ") {
+		t.Errorf("fields: got %v, want %v", tools[0].Fields, want)
+	}
+	if !strings.Contains(tools[0].Description, `e.g. "data,data[].id". Fields: data, data[].id, data[].sku, total.`) {
+		t.Errorf("description should document the fields, got %q", tools[0].Description)
+	}
+	props, _ := tools[0].InputSchema["properties"].(map[string]interface{})
+	if props["fields"] == nil {
+		t.Errorf("input schema should have a fields property, got %v", tools[0].InputSchema)
+	}
+	if tools[1].Fields != nil || tools[2].Fields != nil {
+		t.Errorf("x-mcp-projection: false and operations without a JSON response get no fields argument")
+	}
+	for _, tool := range tools {
+		if len(tool.Warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %+v", tool.Name, tool.Warnings)
+		}
+	}
+}
+
+func TestApplyProjection_PerOperation(t *testing.T) {
+	clash := productsOperation("filterProducts", map[string]interface{}{"x-mcp-projection": true})
+	clash.Parameters = []model.Parameter{{Name: "fields", In: "query"}}
+	ops := []*model.Operation{
+		productsOperation("listProducts", map[string]interface{}{"x-mcp-projection": true}),
+		productsOperation("searchProducts", nil),
+		clash,
+		{Path: "/ping", Method: "GET", OperationID: "ping", Extensions: map[string]interface{}{"x-mcp-projection": true}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	mapping.ApplyProjection(tools, ops, false)
+
+	if tools[0].Fields == nil || tools[1].Fields != nil || tools[2].Fields != nil || tools[3].Fields != nil {
+		t.Errorf("only the first tool should get a fields argument")
+	}
+	if len(tools[2].Warnings) != 1 || !strings.Contains(tools[2].Warnings[0].Message, `query parameter named "fields"`) {
+		t.Errorf("a clashing parameter should be reported, got %+v", tools[2].Warnings)
+	}
+	if len(tools[3].Warnings) != 1 || tools[3].Warnings[0].Pointer != "/paths/~1ping/get/x-mcp-projection" {
+		t.Errorf("a missing response schema should be reported, got %+v", tools[3].Warnings)
+	}
+}
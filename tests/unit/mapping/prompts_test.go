@@ -0,0 +1,93 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestOperationsToMCPPrompts_OnePromptPerTag(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "listOrders", Path: "/orders", Method: "GET", Summary: "List orders", Tags: []string{"orders"}},
+		{OperationID: "createOrder", Path: "/orders", Method: "POST", Summary: "Create order", Tags: []string{"orders"}},
+		{OperationID: "listProducts", Path: "/products", Method: "GET", Summary: "List products", Tags: []string{"products"}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	prompts := mapping.OperationsToMCPPrompts(ops, tools, nil, []model.Tag{{Name: "orders", Description: "Order management"}})
+
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 prompts, got %d", len(prompts))
+	}
+	if prompts[0].Name != "orders_tools" || prompts[1].Name != "products_tools" {
+		t.Errorf("prompt names: got %q, %q", prompts[0].Name, prompts[1].Name)
+	}
+	for _, want := range []string{"Order management", "list_orders", "create_order"} {
+		if !strings.Contains(prompts[0].Text, want) {
+			t.Errorf("orders prompt missing %q:\n%s", want, prompts[0].Text)
+		}
+	}
+	if strings.Contains(prompts[0].Text, "list_products") {
+		t.Error("orders prompt should not list tools from other tags")
+	}
+}
+
+func TestOperationsToMCPPrompts_FromLinks(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "createOrder", Path: "/orders", Method: "POST", Links: []model.Link{
+			{Name: "GetOrder", Status: "201", OperationID: "getOrder", Parameters: map[string]string{"orderId": "$response.body#/id"}},
+			{Name: "Cancel", Status: "201", OperationRef: "#/paths/~1orders~1{orderId}/delete"},
+			{Name: "Missing", Status: "201", OperationID: "doesNotExist"},
+		}},
+		{OperationID: "getOrder", Path: "/orders/{orderId}", Method: "GET"},
+		{OperationID: "cancelOrder", Path: "/orders/{orderId}", Method: "DELETE"},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "")
+	prompts := mapping.OperationsToMCPPrompts(ops, tools, nil, nil)
+
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 link prompts (unresolved link skipped), got %d", len(prompts))
+	}
+	if prompts[0].Name != "create_order_then_get_order" {
+		t.Errorf("prompts[0].Name = %q", prompts[0].Name)
+	}
+	if !strings.Contains(prompts[0].Text, `orderId: the "id" field of the first response body`) {
+		t.Errorf("link prompt should explain parameter mapping:\n%s", prompts[0].Text)
+	}
+	if prompts[1].Name != "create_order_then_cancel_order" {
+		t.Errorf("prompts[1].Name = %q (operationRef should resolve)", prompts[1].Name)
+	}
+}
+
+func TestOperationsToMCPPrompts_LinksToResources(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "createOrder", Path: "/orders", Method: "POST", Tags: []string{"orders"}, Links: []model.Link{
+			{Name: "GetOrder", Status: "201", OperationID: "getOrder", Parameters: map[string]string{"orderId": "$response.body#/id"}},
+		}},
+		{OperationID: "getOrder", Path: "/orders/{orderId}", Method: "GET", Tags: []string{"orders"},
+			Parameters: []model.Parameter{{Name: "orderId", In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}}}},
+	}
+	resources, rest := mapping.OperationsToMCPResources(ops, "")
+	if len(resources) != 1 {
+		t.Fatalf("getOrder should become a resource, got %d", len(resources))
+	}
+	tools := mapping.OperationsToMCPTools(rest, "")
+	prompts := mapping.OperationsToMCPPrompts(ops, tools, resources, nil)
+
+	if len(prompts) != 2 {
+		t.Fatalf("expected a tag prompt and a link prompt, got %+v", prompts)
+	}
+	if !strings.Contains(prompts[0].Text, "- resource api://orders/{orderId}") {
+		t.Errorf("tag prompt should list the resource:\n%s", prompts[0].Text)
+	}
+	link := prompts[1]
+	if link.Name != "create_order_then_"+resources[0].Name {
+		t.Errorf("link prompt name = %q", link.Name)
+	}
+	for _, want := range []string{"1. Call create_order (POST /orders).", "read the resource api://orders/{orderId}, filling in:", "orderId: "} {
+		if !strings.Contains(link.Text, want) {
+			t.Errorf("link prompt missing %q:\n%s", want, link.Text)
+		}
+	}
+}
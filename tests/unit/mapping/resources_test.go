@@ -0,0 +1,76 @@
+package mapping_test
+
+import (
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestOperationToMCPResource_ParameterlessGet(t *testing.T) {
+	op := &model.Operation{OperationID: "listProducts", Path: "/products", Method: "GET", Summary: "List products"}
+	r := mapping.OperationToMCPResource(op, "http://localhost:8080")
+	if r == nil {
+		t.Fatal("expected resource for parameterless GET")
+	}
+	if r.URI != "api://products" {
+		t.Errorf("URI = %q, want %q", r.URI, "api://products")
+	}
+	if r.Name != "list_products" {
+		t.Errorf("Name = %q, want %q", r.Name, "list_products")
+	}
+	if r.IsTemplate() {
+		t.Error("parameterless GET should not be a template")
+	}
+}
+
+func TestOperationToMCPResource_PathParamsOnly_IsTemplate(t *testing.T) {
+	op := &model.Operation{
+		OperationID: "getProduct",
+		Path:        "/products/{productId}",
+		Method:      "GET",
+		Parameters:  []model.Parameter{{Name: "productId", In: "path", Required: true}},
+	}
+	r := mapping.OperationToMCPResource(op, "")
+	if r == nil {
+		t.Fatal("expected resource template for GET with only path params")
+	}
+	if r.URI != "api://products/{productId}" {
+		t.Errorf("URI = %q", r.URI)
+	}
+	if !r.IsTemplate() || r.Params[0].Name != "productId" {
+		t.Errorf("expected template with productId argument, got %+v", r.Params)
+	}
+}
+
+func TestOperationsToMCPResources_SplitsTools(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "listProducts", Path: "/products", Method: "GET"},
+		{OperationID: "searchProducts", Path: "/products/search", Method: "GET",
+			Parameters: []model.Parameter{{Name: "q", In: "query"}}},
+		{OperationID: "createProduct", Path: "/products", Method: "POST"},
+		{OperationID: "getProduct", Path: "/products/{id}", Method: "GET",
+			Parameters: []model.Parameter{{Name: "id", In: "path", Required: true}}},
+	}
+	resources, rest := mapping.OperationsToMCPResources(ops, "")
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(resources))
+	}
+	if len(rest) != 2 || rest[0].OperationID != "searchProducts" || rest[1].OperationID != "createProduct" {
+		t.Errorf("remaining operations: got %+v", rest)
+	}
+}
+
+func TestOperationToMCPResource_ReportsInvalidExtensions(t *testing.T) {
+	op := &model.Operation{
+		OperationID: "listProducts", Path: "/products", Method: "GET",
+		Extensions: map[string]interface{}{"x-mcp-timeout": "soon", "x-mcp-max-response": 500.0},
+	}
+	r := mapping.OperationToMCPResource(op, "")
+	if r.HTTP.MaxResponse != 500 {
+		t.Errorf("valid extensions should apply, got %+v", r.HTTP)
+	}
+	if len(r.Warnings) != 1 || r.Warnings[0].Pointer != "/paths/~1products/get/x-mcp-timeout" {
+		t.Errorf("want one x-mcp-timeout warning, got %+v", r.Warnings)
+	}
+}
@@ -0,0 +1,30 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestOperationToMCPTool_Stream(t *testing.T) {
+	tests := map[string]string{
+		"text/event-stream":                "sse",
+		"text/event-stream; charset=utf-8": "sse",
+		"application/x-ndjson":             "ndjson",
+		"application/jsonl":                "ndjson",
+		"application/json":                 "",
+		"":                                 "",
+	}
+	for contentType, want := range tests {
+		op := &model.Operation{Path: "/events", Method: "GET", OperationID: "watch", ContentType: contentType}
+		tool := mapping.OperationToMCPTool(op, "")
+		if tool.Stream != want || tool.ContentType != contentType {
+			t.Errorf("%q: stream %q, content type %q; want %q", contentType, tool.Stream, tool.ContentType, want)
+		}
+		if (want != "") != strings.Contains(tool.Description, "The response is a stream of events") {
+			t.Errorf("%q: description %q", contentType, tool.Description)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package mapping_test
+
+import (
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/arazzo"
+	"bakemcp/internal/domain/mapping"
+	"bakemcp/internal/domain/model"
+)
+
+func TestWorkflowsToMCPWorkflows_ResolvesSteps(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "createOrder", Path: "/orders", Method: "post"},
+		{OperationID: "getOrder", Path: "/orders/{orderId}", Method: "get",
+			Parameters: []model.Parameter{{Name: "orderId", In: "path", Required: true}}},
+	}
+	tools := mapping.OperationsToMCPTools(ops, "http://api")
+	wfs := []arazzo.Workflow{{
+		WorkflowID: "createOrder",
+		Steps: []arazzo.Step{
+			{StepID: "create", OperationID: "createOrder", Outputs: map[string]string{"id": "$response.body#/id"}},
+			{StepID: "fetch", OperationID: "getOrder", Parameters: []arazzo.Parameter{{Name: "orderId", Value: "$steps.create.outputs.id"}}},
+		},
+	}}
+	got, err := mapping.WorkflowsToMCPWorkflows(wfs, ops, tools, "http://api")
+	if err != nil {
+		t.Fatalf("WorkflowsToMCPWorkflows: %v", err)
+	}
+	w := got[0]
+	if w.Name != "create_order_2" {
+		t.Errorf("Name = %q, want create_order_2 (must not collide with tool create_order)", w.Name)
+	}
+	if len(w.Steps) != 2 || w.Steps[1].Tool.Method != "GET" || w.Steps[1].Tool.Path != "/orders/{orderId}" {
+		t.Fatalf("steps not resolved: %+v", w.Steps)
+	}
+	if w.Steps[1].Params[0].In != "path" {
+		t.Errorf("parameter location should come from the operation, got %q", w.Steps[1].Params[0].In)
+	}
+}
+
+func TestWorkflowsToMCPWorkflows_UnknownOperation(t *testing.T) {
+	wfs := []arazzo.Workflow{{WorkflowID: "wf", Steps: []arazzo.Step{{StepID: "s", OperationID: "missing"}}}}
+	if _, err := mapping.WorkflowsToMCPWorkflows(wfs, nil, nil, ""); err == nil {
+		t.Fatal("expected error for unknown operationId")
+	}
+}
+
+func TestWorkflowsToMCPWorkflows_AmbiguousOperation(t *testing.T) {
+	ops := []*model.Operation{
+		{OperationID: "listOrders", Path: "/orders", Method: "get", Source: &model.Source{Name: "shop"}},
+		{OperationID: "listOrders", Path: "/orders", Method: "get", Source: &model.Source{Name: "billing"}},
+	}
+	wfs := []arazzo.Workflow{{WorkflowID: "wf", Steps: []arazzo.Step{{StepID: "s", OperationID: "listOrders"}}}}
+	_, err := mapping.WorkflowsToMCPWorkflows(wfs, ops, nil, "")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") || !strings.Contains(err.Error(), "GET /orders (shop) and GET /orders (billing)") {
+		t.Fatalf("expected an ambiguity error naming both operations, got %v", err)
+	}
+}
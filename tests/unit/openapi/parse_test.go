@@ -3,9 +3,13 @@ package openapi_test
 import (
 	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"bakemcp/internal/domain/model"
 	"bakemcp/internal/domain/openapi"
 )
 
@@ -21,6 +25,9 @@ func TestParse_ValidOpenAPI3(t *testing.T) {
 	if result.Operations[0].OperationID != "ping" {
 		t.Errorf("operationId: got %q", result.Operations[0].OperationID)
 	}
+	if result.Info != (model.Info{Title: "x", Version: "1.0"}) {
+		t.Errorf("info: got %+v", result.Info)
+	}
 }
 
 func TestParse_RejectOpenAPI2(t *testing.T) {
@@ -40,3 +47,279 @@ func TestParse_RejectInvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON")
 	}
 }
+
+func TestParse_TagsAndLinks(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},
+	"tags":[{"name":"orders","description":"Order management"}],
+	"paths":{
+	  "/orders":{"post":{"operationId":"createOrder","tags":["orders"],"responses":{"201":{"description":"Created",
+	    "links":{"GetOrder":{"operationId":"getOrder","parameters":{"orderId":"$response.body#/id"}}}}}}},
+	  "/orders/{orderId}":{"get":{"operationId":"getOrder","tags":["orders"],
+	    "parameters":[{"name":"orderId","in":"path","required":true,"schema":{"type":"string"}}],
+	    "responses":{"200":{"description":"OK"}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Tags) != 1 || result.Tags[0].Description != "Order management" {
+		t.Errorf("tags: got %+v", result.Tags)
+	}
+	var create *model.Operation
+	for _, op := range result.Operations {
+		if op.OperationID == "createOrder" {
+			create = op
+		}
+	}
+	if create == nil {
+		t.Fatal("createOrder not parsed")
+	}
+	if len(create.Tags) != 1 || create.Tags[0] != "orders" {
+		t.Errorf("operation tags: got %v", create.Tags)
+	}
+	if len(create.Links) != 1 {
+		t.Fatalf("expected 1 link, got %d", len(create.Links))
+	}
+	l := create.Links[0]
+	if l.Name != "GetOrder" || l.Status != "201" || l.OperationID != "getOrder" || l.Parameters["orderId"] != "$response.body#/id" {
+		t.Errorf("link: got %+v", l)
+	}
+}
+
+func TestParse_WarnsOnDroppedParts(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /files/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    put:
+      operationId: putFile
+      requestBody:
+        content:
+          application/octet-stream:
+            schema: {type: string, format: binary}
+      responses:
+        "204": {description: Stored}
+    delete:
+      operationId: deleteFile
+      responses:
+        "204": {description: Deleted}
+`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for _, op := range result.Operations {
+		if op.RequestBody != nil {
+			t.Error("non-JSON request body should be dropped")
+		}
+	}
+	want := map[string]string{
+		"/paths/~1files~1{id}/parameters/0":    "/files/{id}",
+		"/paths/~1files~1{id}/put/requestBody": "PUT /files/{id}",
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("expected %d warnings (path-level parameters once per path), got %+v", len(want), result.Warnings)
+	}
+	for _, w := range result.Warnings {
+		if op, ok := want[w.Pointer]; !ok || w.Stage != "parse" || w.Operation != op {
+			t.Errorf("unexpected warning %+v", w)
+		}
+	}
+}
+
+func TestParse_ParameterPointers(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /search:
+    get:
+      parameters:
+        - {name: q, in: query, schema: {type: string}}
+        - {name: X-Trace, in: header, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	params := result.Operations[0].Parameters
+	if len(params) != 2 || params[0].Pointer != "/parameters/0" || params[1].Pointer != "/parameters/1" {
+		t.Errorf("parameters should point into the operation, got %+v", params)
+	}
+}
+
+func TestParse_OperationExtensions(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{"/ping":{"get":{"operationId":"ping","x-mcp-timeout":5000,"x-mcp-idempotent":true,"responses":{"200":{"description":"ok"}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]interface{}{"x-mcp-timeout": float64(5000), "x-mcp-idempotent": true}
+	if got := result.Operations[0].Extensions; !reflect.DeepEqual(got, want) {
+		t.Errorf("extensions: got %v, want %v", got, want)
+	}
+}
+
+func TestParse_ErrorResponses(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{"/orders/{id}":{"get":{"operationId":"getOrder","parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],"responses":{"200":{"description":"The order"},"404":{"description":"No such order"},"4xx":{"description":"Bad request"},"default":{"description":"Unexpected error"}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{"404": "No such order", "4XX": "Bad request", "default": "Unexpected error"}
+	if got := result.Operations[0].Errors; !reflect.DeepEqual(got, want) {
+		t.Errorf("errors: got %v, want %v", got, want)
+	}
+}
+
+func TestParse_ResponseSchema(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{"/orders":{"get":{"operationId":"listOrders","responses":{
+		"202":{"description":"Accepted","content":{"application/json":{"schema":{"type":"string"}}}},
+		"200":{"description":"The orders","content":{"text/csv":{"schema":{"type":"string"}},"application/vnd.api+json":{"schema":{"type":"array","items":{"type":"object"}}}}},
+		"404":{"description":"Nothing","content":{"application/json":{"schema":{"type":"object"}}}}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := result.Operations[0].Response; got == nil || got["type"] != "array" {
+		t.Errorf("response schema: got %v, want the JSON schema of the 200 response", got)
+	}
+}
+
+func TestParse_ResponseHeaders(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{"/orders":{"get":{"operationId":"listOrders","responses":{
+		"200":{"description":"The orders","headers":{"Link":{"schema":{"type":"string"}},"X-Total-Count":{"schema":{"type":"integer"}}}},
+		"429":{"description":"Slow down","headers":{"Retry-After":{"schema":{"type":"integer"}}}}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{"Link", "X-Total-Count"}
+	if got := result.Operations[0].Headers; !reflect.DeepEqual(got, want) {
+		t.Errorf("headers: got %v, want %v", got, want)
+	}
+}
+
+func TestParse_OperationsSorted(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /b:
+    post: {responses: {"201": {description: ok}}}
+    get: {responses: {"200": {description: ok}}}
+  /a:
+    delete: {responses: {"204": {description: ok}}}
+`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var got []string
+	for _, op := range result.Operations {
+		got = append(got, op.Method+" "+op.Path)
+	}
+	if strings.Join(got, ",") != "DELETE /a,GET /b,POST /b" {
+		t.Errorf("order: got %v", got)
+	}
+}
+
+func TestParseAt_ResolvesLocalRefs(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.3
+info: {title: x, version: "1.0"}
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "schemas/pet.yaml#/Pet"
+      responses:
+        "200": {description: ok}
+`
+	pet := `Pet:
+  type: object
+  properties:
+    name: {type: string}
+    owner:
+      $ref: "owner.yaml"
+`
+	owner := `type: object
+properties:
+  id: {type: string}
+`
+	writeFile(t, filepath.Join(dir, "api.yaml"), spec)
+	writeFile(t, filepath.Join(dir, "schemas", "pet.yaml"), pet)
+	writeFile(t, filepath.Join(dir, "schemas", "owner.yaml"), owner)
+
+	path := filepath.Join(dir, "api.yaml")
+	result, err := openapi.ParseAt(strings.NewReader(spec), path)
+	if err != nil {
+		t.Fatalf("ParseAt: %v", err)
+	}
+	props, _ := result.Operations[0].RequestBody.Schema["properties"].(map[string]interface{})
+	if _, ok := props["name"]; !ok {
+		t.Errorf("body schema should be resolved from schemas/pet.yaml, got %v", result.Operations[0].RequestBody.Schema)
+	}
+
+	refs := openapi.LocalRefs(path)
+	want := []string{filepath.Join(dir, "schemas", "owner.yaml"), filepath.Join(dir, "schemas", "pet.yaml")}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("LocalRefs: got %v, want %v", refs, want)
+	}
+
+	remote := strings.Replace(spec, "schemas/pet.yaml#/Pet", "https://example.com/schemas.yaml#/Pet", 1)
+	writeFile(t, path, remote)
+	if _, err := openapi.ParseAt(strings.NewReader(remote), path); err == nil {
+		t.Error("ParseAt should not fetch remote refs")
+	}
+	if refs := openapi.LocalRefs(path); len(refs) != 0 {
+		t.Errorf("LocalRefs should skip remote refs, got %v", refs)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParse_Accepted(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{"/exports":{
+		"post":{"operationId":"createExport","responses":{"202":{"description":"Accepted"}}},
+		"get":{"operationId":"listExports","responses":{"200":{"description":"The exports"}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for _, op := range result.Operations {
+		if want := op.Method == "POST"; op.Accepted != want {
+			t.Errorf("%s: Accepted = %v, want %v", op.OperationID, op.Accepted, want)
+		}
+	}
+}
+
+func TestParse_ResponseContentType(t *testing.T) {
+	spec := `{"openapi":"3.0.3","info":{"title":"x","version":"1.0"},"paths":{
+		"/events":{"get":{"operationId":"watch","responses":{"200":{"description":"Events","content":{"text/event-stream":{"schema":{"type":"string"}}}}}}},
+		"/orders":{"get":{"operationId":"list","responses":{"200":{"description":"Orders","content":{"text/csv":{},"application/json":{}}}}}},
+		"/ping":{"get":{"operationId":"ping","responses":{"204":{"description":"Pong"}}}}}}`
+	result, err := openapi.Parse(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]string{"watch": "text/event-stream", "list": "application/json", "ping": ""}
+	for _, op := range result.Operations {
+		if op.ContentType != want[op.OperationID] {
+			t.Errorf("%s: content type %q, want %q", op.OperationID, op.ContentType, want[op.OperationID])
+		}
+	}
+}
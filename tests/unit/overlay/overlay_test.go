@@ -0,0 +1,157 @@
+package overlay_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bakemcp/internal/domain/openapi"
+	"bakemcp/internal/domain/overlay"
+)
+
+const spec = `openapi: 3.0.3
+info:
+  title: Shop
+  version: 1.0.0
+paths:
+  /products:
+    get:
+      operationId: listProducts
+      summary: old summary
+      tags: [products]
+      responses:
+        200:
+          description: OK
+    post:
+      operationId: createProduct
+      tags: [admin]
+      responses:
+        201:
+          description: Created
+  /internal/health:
+    get:
+      operationId: health
+      tags: [internal]
+      responses:
+        200:
+          description: OK
+`
+
+func apply(t *testing.T, ovDoc string) map[string]interface{} {
+	t.Helper()
+	ov, err := overlay.Parse(strings.NewReader(ovDoc))
+	if err != nil {
+		t.Fatalf("Parse overlay: %v", err)
+	}
+	out, err := overlay.Apply([]byte(spec), ov)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("Apply produced invalid JSON: %v", err)
+	}
+	return doc
+}
+
+func TestApply_UpdateAndRemove(t *testing.T) {
+	doc := apply(t, `overlay: 1.0.0
+info: {title: fixes, version: 1.0.0}
+actions:
+  - target: $.paths['/products'].get
+    update:
+      summary: List all products
+      x-mcp-timeout: 5000
+  - target: $.paths['/internal/health']
+    remove: true
+  - target: $.paths.*.*.tags
+    update: reviewed
+`)
+	paths := doc["paths"].(map[string]interface{})
+	if _, ok := paths["/internal/health"]; ok {
+		t.Error("remove action should delete /internal/health")
+	}
+	get := paths["/products"].(map[string]interface{})["get"].(map[string]interface{})
+	if get["summary"] != "List all products" || get["x-mcp-timeout"] != float64(5000) {
+		t.Errorf("update action not merged: %v", get)
+	}
+	tags := get["tags"].([]interface{})
+	if len(tags) != 2 || tags[1] != "reviewed" {
+		t.Errorf("update on array should append, got %v", tags)
+	}
+	if _, ok := get["responses"].(map[string]interface{})["200"]; !ok {
+		t.Error("unquoted YAML response codes should survive as string keys")
+	}
+}
+
+func TestApply_FilterAndRecursiveDescent(t *testing.T) {
+	doc := apply(t, `overlay: 1.0.0
+actions:
+  - target: $.paths.*[?(@.operationId == 'createProduct')]
+    remove: true
+  - target: $..[?@.operationId == 'nope' || @.summary]
+    update: {deprecated: true}
+`)
+	products := doc["paths"].(map[string]interface{})["/products"].(map[string]interface{})
+	if _, ok := products["post"]; ok {
+		t.Error("filter should select createProduct for removal")
+	}
+	if products["get"].(map[string]interface{})["deprecated"] != true {
+		t.Error("recursive filter should select operations with a summary")
+	}
+}
+
+func TestApply_NegatedFilters(t *testing.T) {
+	doc := apply(t, `overlay: 1.0.0
+actions:
+  - target: $.paths.*[?(!(@.tags[0] == 'admin') && !@.summary)]
+    update: {x-internal: true}
+`)
+	paths := doc["paths"].(map[string]interface{})
+	op := func(path, method string) map[string]interface{} {
+		return paths[path].(map[string]interface{})[method].(map[string]interface{})
+	}
+	if op("/internal/health", "get")["x-internal"] != true {
+		t.Error("negated filters should select health")
+	}
+	if _, ok := op("/products", "get")["x-internal"]; ok {
+		t.Error("!@.summary should exclude listProducts")
+	}
+	if _, ok := op("/products", "post")["x-internal"]; ok {
+		t.Error("!(...) should exclude createProduct")
+	}
+}
+
+func TestApply_TargetMatchesNothing(t *testing.T) {
+	ov, err := overlay.Parse(strings.NewReader(`overlay: 1.0.0
+actions:
+  - target: $.paths['/missing'].get
+    remove: true
+`))
+	if err != nil {
+		t.Fatalf("Parse overlay: %v", err)
+	}
+	_, err = overlay.Apply([]byte(spec), ov)
+	if err == nil || !strings.Contains(err.Error(), "matched nothing") {
+		t.Fatalf("expected matched-nothing error, got %v", err)
+	}
+}
+
+func TestApply_ResultParses(t *testing.T) {
+	ov, err := overlay.Parse(strings.NewReader(`{"overlay":"1.0.0","actions":[{"target":"$.paths['/products'].post","remove":true}]}`))
+	if err != nil {
+		t.Fatalf("Parse overlay: %v", err)
+	}
+	out, err := overlay.Apply([]byte(spec), ov)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	result, err := openapi.Parse(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("openapi.Parse after overlay: %v", err)
+	}
+	if len(result.Operations) != 2 {
+		t.Errorf("expected 2 operations after removing createProduct, got %d", len(result.Operations))
+	}
+}